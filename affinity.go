@@ -0,0 +1,55 @@
+package mclbench
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pinned wraps the body of c so that it runs on an OS thread restricted to
+// cpus. testing.Benchmark calls the body from fresh goroutines, so the pinning
+// happens inside it. The thread is never unlocked: it exits with the goroutine
+// instead of returning to the scheduler with a narrowed affinity mask.
+func pinned(c Case, cpus []int) Case {
+	if len(cpus) == 0 {
+		return c
+	}
+	bench := c.Bench
+	c.Bench = func(b *testing.B, fx *Fixtures, n int) {
+		runtime.LockOSThread()
+		if err := setAffinity(cpus); err != nil {
+			b.Fatal(err)
+		}
+		bench(b, fx, n)
+	}
+	return c
+}
+
+// checkAffinity reports whether pinning to cpus works, without pinning the caller.
+func checkAffinity(cpus []int) error {
+	if len(cpus) == 0 {
+		return nil
+	}
+	errc := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		errc <- setAffinity(cpus)
+	}()
+	return <-errc
+}
+
+// procsKey is the results key of a case run with GOMAXPROCS p, when several
+// values of GOMAXPROCS are swept.
+func procsKey(key string, p int) string {
+	return fmt.Sprintf("%s_p%d", key, p)
+}
+
+func joinInts(xs []int) string {
+	s := make([]string, len(xs))
+	for i, x := range xs {
+		s[i] = strconv.Itoa(x)
+	}
+	return strings.Join(s, ",")
+}
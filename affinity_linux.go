@@ -0,0 +1,23 @@
+package mclbench
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// setAffinity restricts the calling OS thread to cpus.
+func setAffinity(cpus []int) error {
+	var mask [16]uint64 // 1024 CPUs, the kernel's default cpu_set_t
+	for _, c := range cpus {
+		if c < 0 || c >= 64*len(mask) {
+			return fmt.Errorf("CPU %d out of range", c)
+		}
+		mask[c/64] |= 1 << uint(c%64)
+	}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return fmt.Errorf("sched_setaffinity %v: %v", cpus, errno)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package mclbench
+
+import "errors"
+
+func setAffinity(cpus []int) error {
+	return errors.New("CPU pinning is only supported on Linux")
+}
@@ -0,0 +1,56 @@
+package mclbench
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+var (
+	benchCurves = flag.String("curves", "bls12-381", "comma-separated curves to benchmark")
+	benchSize   = flag.Uint64("size", 1000, "elements per fixture")
+)
+
+// benchGroup runs every registered case of group through Benchmark.
+func benchGroup(b *testing.B, group string) {
+	cfg := DefaultConfig()
+	cfg.Size = *benchSize
+	cfg.Sweep = append([]int{2, 5, 32}, int(*benchSize))
+
+	var cases []Case
+	for _, c := range Cases() {
+		if c.Group == group {
+			cases = append(cases, c)
+		}
+	}
+	Benchmark(b, cfg, strings.Split(*benchCurves, ","), cases)
+}
+
+// benchmarkedGroups lists the groups that have a BenchmarkXxx below.
+var benchmarkedGroups = []string{"G1", "G2", "Fr", "GT", "MillerLoop", "Pairing", "IsEqual", "Precomputed", "FixedBase", "Pippenger", "Multilinear", "Pedersen", "IPA"}
+
+func TestEveryGroupIsBenchmarked(t *testing.T) {
+	have := make(map[string]bool)
+	for _, g := range benchmarkedGroups {
+		have[g] = true
+	}
+	for _, g := range Groups() {
+		if !have[g] {
+			t.Errorf("group %s has no BenchmarkXxx function", g)
+		}
+	}
+}
+
+func BenchmarkG1(b *testing.B)          { benchGroup(b, "G1") }
+func BenchmarkG2(b *testing.B)          { benchGroup(b, "G2") }
+func BenchmarkFr(b *testing.B)          { benchGroup(b, "Fr") }
+func BenchmarkGT(b *testing.B)          { benchGroup(b, "GT") }
+func BenchmarkMillerLoop(b *testing.B)  { benchGroup(b, "MillerLoop") }
+func BenchmarkPairing(b *testing.B)     { benchGroup(b, "Pairing") }
+func BenchmarkIsEqual(b *testing.B)     { benchGroup(b, "IsEqual") }
+func BenchmarkPrecomputed(b *testing.B) { benchGroup(b, "Precomputed") }
+func BenchmarkFixedBase(b *testing.B)   { benchGroup(b, "FixedBase") }
+func BenchmarkPippenger(b *testing.B)   { benchGroup(b, "Pippenger") }
+func BenchmarkMultilinear(b *testing.B) { benchGroup(b, "Multilinear") }
+func BenchmarkPedersen(b *testing.B)    { benchGroup(b, "Pedersen") }
+func BenchmarkIPA(b *testing.B)         { benchGroup(b, "IPA") }
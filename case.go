@@ -0,0 +1,100 @@
+package mclbench
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// A Case is one benchmark body run against shared fixtures.
+//
+// A per-element case loops over the whole fixture and is recorded under Name
+// as ns per element. A Vector case is timed once per vector of n elements for
+// every sweep size and is recorded as Name<n> (total) and Name<n>Avg; "{n}"
+// in Name marks where the size goes when it is not at the end. A Single case
+// is one operation per iteration (n is 1) and is recorded under Name.
+type Case struct {
+	Name   string
+	Group  string
+	Vector bool
+	Single bool
+	Unit   string // one element of a vector case, e.g. "exp" or "pairing"
+	Bench  func(b *testing.B, fx *Fixtures, n int)
+}
+
+// Key is the results key of the case at size n.
+func (c Case) Key(n int) string {
+	if !c.Vector {
+		return c.Name
+	}
+	if strings.Contains(c.Name, "{n}") {
+		return strings.Replace(c.Name, "{n}", strconv.Itoa(n), 1)
+	}
+	return c.Name + strconv.Itoa(n)
+}
+
+// Label is the case name as printed in summaries.
+func (c Case) Label() string {
+	return strings.Replace(c.Name, "{n}", "", 1)
+}
+
+var registry []Case
+
+// Register adds a case to the suite. Cases run in registration order.
+func Register(c Case) {
+	for _, r := range registry {
+		if r.Name == c.Name {
+			panic(fmt.Sprintf("mclbench: case %s registered twice", c.Name))
+		}
+	}
+	registry = append(registry, c)
+}
+
+// Cases returns every registered case.
+func Cases() []Case {
+	return append([]Case(nil), registry...)
+}
+
+// Select returns the registered cases whose name or group matches pattern.
+func Select(pattern string) ([]Case, error) {
+	if pattern == "" {
+		return Cases(), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var out []Case
+	for _, c := range registry {
+		if re.MatchString(c.Name) || re.MatchString(c.Group) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// Groups returns the group names in registration order.
+func Groups() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range registry {
+		if !seen[c.Group] {
+			seen[c.Group] = true
+			out = append(out, c.Group)
+		}
+	}
+	return out
+}
+
+func init() {
+	registerExponentiation()
+	registerPairing()
+	registerPrecomputedPairing()
+	registerFixedBase()
+	registerPippenger()
+	registerMultilinear()
+	registerPedersen()
+	registerIPA()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+// varFlags collects repeated -var name=value flags.
+type varFlags map[string]float64
+
+func (v varFlags) String() string { return fmt.Sprint(map[string]float64(v)) }
+
+func (v varFlags) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	f, err := strconv.ParseFloat(kv[1], 64)
+	if err != nil {
+		return err
+	}
+	v[strings.TrimSpace(kv[0])] = f
+	return nil
+}
+
+func Estimate(args []string) {
+	fs := flag.NewFlagSet("estimate", flag.ExitOnError)
+	in := fs.String("i", "benchmarking-results-nanoseconds.json", "results file")
+	path := fs.String("f", "formulas.txt", "formula file, one name = formula per line")
+	vars := make(varFlags)
+	fs.Var(vars, "var", "override a variable, name=value (repeatable)")
+	fs.Parse(args)
+
+	db, err := mclbench.LoadResults(*in)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	formulas, err := mclbench.ParseFormulas(*path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	e := mclbench.NewEstimator(db, formulas, vars)
+	for _, f := range formulas {
+		// Plain numbers are variables, not costs.
+		if _, ok := vars[f.Name]; ok || f.IsConstant() {
+			continue
+		}
+		total, err := e.Estimate(f.Name)
+		if err != nil {
+			fmt.Printf("%s: %v\n", f.Name, err)
+			continue
+		}
+		fmt.Printf("%-60s %20.3f us %s\n", f.Name+":", total.Ns/1000, boundString(total.Bound))
+		vals, errs := e.Breakdown(f)
+		for i, v := range vals {
+			if errs[i] != nil {
+				fmt.Printf("    %-56s %v\n", f.Labels[i], errs[i])
+				continue
+			}
+			share := 0.0
+			if total.Ns != 0 {
+				share = 100 * v.Ns / total.Ns
+			}
+			fmt.Printf("    %-56s %20.3f us %6.1f%%\n", f.Labels[i], v.Ns/1000, share)
+		}
+		fmt.Println(mclbench.SepString(""))
+	}
+}
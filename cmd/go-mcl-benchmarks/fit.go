@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func Fit(args []string) {
+	fs := flag.NewFlagSet("fit", flag.ExitOnError)
+	in := fs.String("i", "benchmarking-results-nanoseconds.json", "results file")
+	cases := fs.String("cases", "", "comma-separated cases to fit (default: every case recorded at 3+ sizes)")
+	model := fs.String("model", "", "model to predict with: linear, msm or nlogn (default: per case)")
+	at := fs.String("at", "2^10,2^16,2^20", "comma-separated sizes to predict, 2^k allowed")
+	out := fs.String("o", "", "also write the fits as JSON to this file")
+	fs.Parse(args)
+
+	db, err := mclbench.LoadResults(*in)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	sizes, err := mclbench.ParseSizes(*at)
+	if err != nil {
+		fmt.Println("bad -at:", err)
+		os.Exit(2)
+	}
+	if *model != "" {
+		if _, err := mclbench.FitModelByName(*model); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	// Results recorded with several GOMAXPROCS values are fitted per value.
+	views := mclbench.ProcsViews(db)
+	procs := make([]int, 0, len(views))
+	for p := range views {
+		procs = append(procs, p)
+	}
+	sort.Ints(procs)
+
+	var fits []mclbench.Fit
+	for _, p := range procs {
+		view := views[p]
+		names := mclbench.SweptCases(view)
+		if *cases != "" {
+			names = strings.Split(*cases, ",")
+		}
+		for _, name := range names {
+			label := name
+			if p != 0 {
+				label = fmt.Sprintf("%s_p%d", name, p)
+			}
+			chosen := *model
+			if chosen == "" {
+				chosen = mclbench.DefaultModel(name)
+			}
+			var best mclbench.Fit
+			for _, m := range mclbench.FitModels {
+				f, err := mclbench.FitCase(view, name, m)
+				if err != nil {
+					if p != 0 {
+						fmt.Printf("%v (GOMAXPROCS %d)\n", err, p)
+					} else {
+						fmt.Println(err)
+					}
+					break
+				}
+				mark := " "
+				if m.Name == chosen {
+					mark = "*"
+					best = f
+				}
+				fmt.Printf("%s %-22s %-18s a=%14.4g b=%14.4g R^2=%8.5f (%d sizes)\n", mark, label, m.Formula, f.A, f.B, f.R2, f.Points)
+			}
+			if best.Case == "" {
+				continue
+			}
+			best.Case = label
+			fits = append(fits, best)
+			for _, n := range sizes {
+				y, bound := best.Predict(n)
+				line := fmt.Sprintf("Predicted %s(%s) [%s]:", label, humanize.Comma(int64(n)), best.Model)
+				fmt.Printf("%-60s %20.3f us %s\n", line, y/1000, boundString(bound))
+			}
+			fmt.Println(mclbench.SepString(""))
+		}
+	}
+
+	if *out != "" {
+		json, err := json.MarshalIndent(fits, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		err = ioutil.WriteFile(*out, json, 0666)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("Data saved to:", *out)
+	}
+}
+
+// boundString formats the half-width of a 95% interval in ns; fits through
+// two points have none.
+func boundString(bound float64) string {
+	if math.IsInf(bound, 0) || math.IsNaN(bound) {
+		return "(no bound)"
+	}
+	return fmt.Sprintf("+/- %.3f us (95%%)", bound/1000)
+}
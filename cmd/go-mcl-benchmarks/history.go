@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func History(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	in := fs.String("i", "benchmarking-history.jsonl", "history file")
+	cases := fs.String("cases", "", "regexp selecting the cases to show")
+	curve := fs.String("curve", "", "only runs on this curve (default: all)")
+	machine := fs.String("fingerprint", mclbench.Fingerprint(), "only runs on this machine; empty for all")
+	threshold := fs.Float64("threshold", 0.1, "relative change reported as a step")
+	steps := fs.Bool("steps", false, "only show cases with a step change")
+	fs.Parse(args)
+
+	entries, err := mclbench.LoadHistory(*in)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	filter := mclbench.HistoryFilter{Fingerprint: *machine, Curve: *curve}
+	if *cases != "" {
+		if filter.Cases, err = regexp.Compile(*cases); err != nil {
+			fmt.Println("bad -cases:", err)
+			os.Exit(2)
+		}
+	}
+
+	for _, t := range mclbench.Trends(entries, filter, *threshold) {
+		if *steps && len(t.Steps) == 0 {
+			continue
+		}
+		values := make([]float64, len(t.Points))
+		for i, p := range t.Points {
+			values[i] = p.Value
+		}
+		last := t.Points[len(t.Points)-1]
+		fmt.Printf("%-40s %4d runs %16.3f -> %16.3f %+8.1f%%  %s\n", t.Case, len(t.Points), t.Points[0].Value, last.Value, 100*t.Change(), mclbench.Sparkline(values))
+		for _, s := range t.Steps {
+			p := t.Points[s.Index]
+			fmt.Printf("    step at %s (%s): %.3f -> %.3f %+.1f%%\n", p.Time.Format("2006-01-02 15:04"), p.MclVersion, s.Before, s.After, 100*s.Change)
+		}
+	}
+}
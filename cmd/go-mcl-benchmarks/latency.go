@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func Latency(args []string, size uint64) {
+	fs := flag.NewFlagSet("latency", flag.ExitOnError)
+	n := fs.Int("n", 10000, "timed calls per operation")
+	warmup := fs.Int("warmup", 100, "untimed calls per operation")
+	ops := fs.String("ops", "G1Mul,G2Mul,MillerLoop,FinalExp,Pairing,G1MulVec", "comma-separated operations")
+	msm := fs.Int("msm", 256, "size of the G1MulVec operation")
+	out := fs.String("o", "latency-results-nanoseconds.json", "results file; histograms go next to it")
+	fs.Parse(args)
+	if *msm < 1 {
+		fmt.Println("bad -msm: must be at least 1")
+		os.Exit(2)
+	}
+
+	cfg := mclbench.LatencyConfig{N: *n, Warmup: *warmup, MSMSize: *msm, Progress: os.Stdout}
+	for _, s := range strings.Split(*ops, ",") {
+		cfg.Ops = append(cfg.Ops, strings.TrimSpace(s))
+	}
+
+	env := mclbench.CaptureEnvironment()
+	for _, l := range env.Lines() {
+		fmt.Println(l)
+	}
+	fmt.Println(mclbench.SepString(""))
+	results, err := mclbench.RunLatency(mclbench.NewFixtures(size), cfg)
+	if err != nil {
+		fmt.Println("bad -ops:", err)
+		os.Exit(2)
+	}
+
+	if err := mclbench.SaveResults(*out, mclbench.LatencyValues(results)); err != nil {
+		panic(err)
+	}
+	if err := mclbench.SaveLatency(mclbench.HistogramPath(*out), results); err != nil {
+		panic(err)
+	}
+	if err := mclbench.SaveEnvironment(mclbench.EnvPath(*out), env); err != nil {
+		panic(err)
+	}
+	fmt.Println("Data saved to:", *out)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func Leakage(args []string) {
+	fs := flag.NewFlagSet("leakage", flag.ExitOnError)
+	n := fs.Int("n", 20000, "measurements per operation and class pair")
+	checkpoint := fs.Int("checkpoint", 2000, "report the t-statistic every this many measurements")
+	ops := fs.String("ops", "G1Mul,G1MulCT,G2Mul,GTPow", "comma-separated operations to test")
+	out := fs.String("o", "leakage-results.json", "output file")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed for class selection")
+	fs.Parse(args)
+	if *n <= 0 {
+		fmt.Println("bad -n: must be positive")
+		os.Exit(2)
+	}
+	if *checkpoint < mclbench.MinLeakageCheckpoint {
+		fmt.Printf("bad -checkpoint: must be at least %d\n", mclbench.MinLeakageCheckpoint)
+		os.Exit(2)
+	}
+
+	var names []string
+	for _, s := range strings.Split(*ops, ",") {
+		names = append(names, strings.TrimSpace(s))
+	}
+	results, err := mclbench.RunLeakage(mclbench.LeakageConfig{
+		N:          *n,
+		Checkpoint: *checkpoint,
+		Ops:        names,
+		Seed:       *seed,
+		Progress:   os.Stdout,
+	})
+	if err != nil {
+		fmt.Println("bad -ops:", err)
+		os.Exit(2)
+	}
+
+	for _, r := range results {
+		verdict := "ok"
+		if r.Leaks {
+			verdict = "LEAKS"
+		}
+		fmt.Printf("%-10s %-18s max |t| = %8.3f  %s\n", r.Op, r.Classes, r.MaxT, verdict)
+	}
+
+	json, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(*out, json, 0666); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("Data saved to:", *out)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alinush/go-mcl"
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// childCommand re-runs this binary with the parent's flags for one case.
+// Later flags win, so the overrides only need to be appended.
+func childCommand(seed int64) func(c mclbench.Case) *exec.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	parent := os.Args[1 : len(os.Args)-flag.NArg()]
+	return func(c mclbench.Case) *exec.Cmd {
+		args := append(append([]string(nil), parent...),
+			"-isolate=false", "-strict=false", "-max-load=0", "-history=",
+			"-seed="+strconv.FormatInt(seed, 10), "-child="+c.Name)
+		cmd := exec.Command(exe, args...)
+		cmd.Stderr = os.Stderr
+		return cmd
+	}
+}
+
+func main() {
+	testing.Init()
+	curve := flag.String("curve", "bls12-381", "curve: bls12-381, bn254 or bn254_snark")
+	size := flag.Uint64("size", 1000, "elements per fixture; per-element cases loop over all of them")
+	sweep := flag.String("sweep", "2,5,32", "comma-separated vector sizes, in addition to -size")
+	cases := flag.String("cases", "", "regexp selecting the cases or groups to run")
+	out := flag.String("o", "benchmarking-results-nanoseconds.json", "results file")
+	warmup := flag.Int("warmup", 0, "untimed runs of each case before it is timed")
+	maxLoad := flag.Float64("max-load", 1, "warn when the 1-minute load average is above this; 0 to ignore")
+	strict := flag.Bool("strict", false, "refuse to run when the governor, turbo or load checks fail")
+	cpus := flag.String("cpus", "", "comma-separated CPUs to pin the timed thread to (Linux only)")
+	procs := flag.String("procs", "", "comma-separated GOMAXPROCS values to run every case with")
+	isolate := flag.Bool("isolate", false, "run each case in a fresh process")
+	timeout := flag.Duration("timeout", 0, "with -isolate, kill a case after this long; 0 for no limit")
+	seed := flag.Int64("seed", 0, "derive the fixtures from this seed; with -isolate a random seed is shared by all cases")
+	child := flag.String("child", "", "internal: run one case for an -isolate parent")
+	nogc := flag.Bool("nogc", false, "collect before each case and disable the GC while it is timed")
+	history := flag.String("history", "benchmarking-history.jsonl", "history file the run is appended to; empty to disable")
+	flag.Parse()
+	fmt.Println("Hello, World!")
+	mcl.InitFromString(*curve)
+
+	cfg := mclbench.DefaultConfig()
+	cfg.Curve = *curve
+	if *size < 1 {
+		fmt.Println("bad -size: must be at least 1")
+		os.Exit(2)
+	}
+	cfg.Size = *size
+	sizes, err := parseInts(*sweep)
+	if err != nil {
+		fmt.Println("bad -sweep:", err)
+		os.Exit(2)
+	}
+	cfg.Sweep = nil
+	seen := make(map[int]bool)
+	for _, n := range append(sizes, int(*size)) {
+		if n < 1 {
+			fmt.Println("bad -sweep: sizes must be at least 1")
+			os.Exit(2)
+		}
+		if !seen[n] {
+			seen[n] = true
+			cfg.Sweep = append(cfg.Sweep, n)
+		}
+	}
+	cfg.Warmup = *warmup
+	cfg.MaxLoad = *maxLoad
+	cfg.Strict = *strict
+	cfg.Seed = *seed
+	cfg.DisableGC = *nogc
+	if *isolate && cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if cfg.CPUs, err = parseInts(*cpus); err != nil {
+		fmt.Println("bad -cpus:", err)
+		os.Exit(2)
+	}
+	if cfg.Procs, err = parseInts(*procs); err != nil {
+		fmt.Println("bad -procs:", err)
+		os.Exit(2)
+	}
+
+	if *child != "" {
+		if err := mclbench.RunChild(cfg, *child); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	selected, err := mclbench.Select(*cases)
+	if err != nil {
+		fmt.Println("bad -cases:", err)
+		os.Exit(2)
+	}
+
+	reporters := []mclbench.Reporter{mclbench.NewTextReporter(os.Stdout), &mclbench.JSONReporter{Path: *out}}
+	if *history != "" {
+		reporters = append(reporters, &mclbench.HistoryReporter{Path: *history})
+	}
+	runner := mclbench.NewRunner(cfg, reporters...)
+	if *isolate {
+		runner.Isolation = &mclbench.Isolation{Command: childCommand(cfg.Seed), Timeout: *timeout}
+	}
+
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "leakage":
+			Leakage(flag.Args()[1:])
+		case "fit":
+			Fit(flag.Args()[1:])
+		case "estimate":
+			Estimate(flag.Args()[1:])
+		case "history":
+			History(flag.Args()[1:])
+		case "latency":
+			Latency(flag.Args()[1:], *size)
+		case "workingset":
+			WorkingSet(flag.Args()[1:])
+		case "report":
+			Report(flag.Args()[1:])
+		case "serve":
+			Serve(flag.Args()[1:], runner, selected, *history)
+		default:
+			fmt.Printf("Unknown command: %s\n", flag.Arg(0))
+			os.Exit(2)
+		}
+		return
+	}
+
+	if _, err := runner.Run(selected); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("Data saved to:", *out)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func Report(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	out := fs.String("o", "benchmarking-report.html", "output file")
+	title := fs.String("title", "go-mcl benchmarks", "page title")
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"benchmarking-results-nanoseconds.json"}
+	}
+	var sets []mclbench.ResultSet
+	for _, arg := range files {
+		// label=path, or the file name without extension.
+		label, path := strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg)), arg
+		if i := strings.Index(arg, "="); i >= 0 {
+			label, path = arg[:i], arg[i+1:]
+		}
+		db, err := mclbench.LoadResults(path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		set := mclbench.ResultSet{Label: label, Values: db}
+		if env, err := mclbench.LoadEnvironment(mclbench.EnvPath(path)); err == nil {
+			set.Env = env
+		}
+		if lat, err := mclbench.LoadLatency(mclbench.HistogramPath(path)); err == nil {
+			set.Latency = lat
+		}
+		sets = append(sets, set)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		panic(err)
+	}
+	if err := mclbench.WriteHTMLReport(f, *title, sets); err != nil {
+		panic(err)
+	}
+	if err := f.Close(); err != nil {
+		panic(err)
+	}
+	fmt.Println("Report saved to:", *out)
+}
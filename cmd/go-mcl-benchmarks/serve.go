@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+// Serve runs the suite while serving its progress and the history of this
+// machine, then keeps serving until interrupted.
+func Serve(args []string, runner *mclbench.Runner, cases []mclbench.Case, history string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "listen address")
+	run := fs.Bool("run", true, "run the suite; false only serves the history")
+	fs.Parse(args)
+
+	dash := mclbench.NewDashboard(history)
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Dashboard at http://%s/\n", ln.Addr())
+	errc := make(chan error, 1)
+	go func() { errc <- http.Serve(ln, dash.Handler()) }()
+
+	if *run {
+		runner.Reporters = append(runner.Reporters, dash)
+		if _, err := runner.Run(cases); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("Run finished; still serving, interrupt to exit")
+	}
+	fmt.Println(<-errc)
+	os.Exit(1)
+}
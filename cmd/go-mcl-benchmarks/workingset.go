@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	mclbench "github.com/sshravan/go-mcl-benchmarks"
+)
+
+func WorkingSet(args []string) {
+	fs := flag.NewFlagSet("workingset", flag.ExitOnError)
+	ops := fs.String("ops", "G1Add,G1Mul,FrMul,Pairing", "comma-separated operations")
+	sizes := fs.String("sizes", "", "comma-separated working-set sizes in bytes (default: around each cache level)")
+	max := fs.Int("max", 1<<30, "largest working set in bytes")
+	access := fs.String("access", "seq,rand", "access patterns: seq, rand or both")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed for the random access order")
+	out := fs.String("o", "workingset-results-nanoseconds.json", "results file")
+	fs.Parse(args)
+
+	env := mclbench.CaptureEnvironment()
+	cfg := mclbench.WorkingSetConfig{Seed: *seed, Progress: os.Stdout}
+	for _, s := range strings.Split(*ops, ",") {
+		cfg.Ops = append(cfg.Ops, strings.TrimSpace(s))
+	}
+	cfg.Sizes = mclbench.WorkingSetSizes(env.Caches, *max)
+	if *sizes != "" {
+		var err error
+		if cfg.Sizes, err = parseInts(*sizes); err != nil {
+			fmt.Println("bad -sizes:", err)
+			os.Exit(2)
+		}
+	}
+	for _, a := range strings.Split(*access, ",") {
+		switch strings.TrimSpace(a) {
+		case "seq":
+			cfg.Access = append(cfg.Access, false)
+		case "rand":
+			cfg.Access = append(cfg.Access, true)
+		default:
+			fmt.Println("bad -access:", a)
+			os.Exit(2)
+		}
+	}
+
+	for _, l := range env.Lines() {
+		fmt.Println(l)
+	}
+	fmt.Println(mclbench.SepString(""))
+	points, err := mclbench.RunWorkingSet(cfg)
+	if err != nil {
+		fmt.Println("bad -ops:", err)
+		os.Exit(2)
+	}
+	if err := mclbench.SaveResults(*out, mclbench.WorkingSetValues(points)); err != nil {
+		panic(err)
+	}
+	if err := mclbench.SaveEnvironment(mclbench.EnvPath(*out), env); err != nil {
+		panic(err)
+	}
+	fmt.Println("Data saved to:", *out)
+}
@@ -0,0 +1,156 @@
+package mclbench
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// Dashboard is a Reporter that serves the progress of the current run and
+// the history of this machine over HTTP:
+//
+//	/              live page
+//	/api/progress  the current run
+//	/api/runs      past runs in the history file
+//	/api/history   ?case=Key, the trend of one case on this machine
+type Dashboard struct {
+	History string // history file, may be empty
+
+	mu       sync.Mutex
+	progress dashboardProgress
+}
+
+type dashboardProgress struct {
+	Curve    string             `json:"curve"`
+	Started  time.Time          `json:"started"`
+	Planned  int                `json:"planned"`
+	Done     int                `json:"done"`
+	Last     string             `json:"last"`
+	Finished bool               `json:"finished"`
+	Results  []Result           `json:"results"`
+	Derived  map[string]float64 `json:"derived,omitempty"`
+	Env      *Environment       `json:"env,omitempty"`
+}
+
+func NewDashboard(history string) *Dashboard {
+	return &Dashboard{History: history}
+}
+
+func (d *Dashboard) Start(run *Run) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.progress = dashboardProgress{Curve: run.Curve, Started: run.Started, Planned: run.Planned, Env: run.Env}
+}
+
+func (d *Dashboard) Result(c Case, r Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.progress.Done++
+	d.progress.Last = r.Key
+	d.progress.Results = append(d.progress.Results, r)
+}
+
+func (d *Dashboard) Finish(run *Run) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.progress.Finished = true
+	d.progress.Derived = run.Derived
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (d *Dashboard) serveProgress(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	p := d.progress
+	p.Results = append([]Result(nil), p.Results...)
+	d.mu.Unlock()
+	writeJSON(w, p)
+}
+
+func (d *Dashboard) loadHistory(w http.ResponseWriter) ([]HistoryEntry, bool) {
+	if d.History == "" {
+		writeJSON(w, []interface{}{})
+		return nil, false
+	}
+	entries, err := LoadHistory(d.History)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return entries, true
+}
+
+func (d *Dashboard) serveRuns(w http.ResponseWriter, r *http.Request) {
+	entries, ok := d.loadHistory(w)
+	if !ok {
+		return
+	}
+	type runSummary struct {
+		Fingerprint string    `json:"fingerprint"`
+		Host        string    `json:"host"`
+		Curve       string    `json:"curve"`
+		MclVersion  string    `json:"mcl_version"`
+		Time        time.Time `json:"time"`
+		Cases       int       `json:"cases"`
+	}
+	out := make([]runSummary, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, runSummary{e.Fingerprint, e.Host, e.Curve, e.MclVersion, e.Time, len(e.Values)})
+	}
+	writeJSON(w, out)
+}
+
+func (d *Dashboard) serveHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("case")
+	if name == "" {
+		http.Error(w, "missing case", http.StatusBadRequest)
+		return
+	}
+	entries, ok := d.loadHistory(w)
+	if !ok {
+		return
+	}
+	d.mu.Lock()
+	curve := d.progress.Curve
+	d.mu.Unlock()
+	filter := HistoryFilter{
+		Fingerprint: Fingerprint(),
+		Curve:       curve,
+		Cases:       regexp.MustCompile("^" + regexp.QuoteMeta(name) + "$"),
+	}
+	trends := Trends(entries, filter, 0.1)
+	if len(trends) == 0 {
+		writeJSON(w, Trend{Case: name, Points: []TrendPoint{}})
+		return
+	}
+	writeJSON(w, trends[0])
+}
+
+// Handler returns the HTTP handler of the dashboard.
+func (d *Dashboard) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(dashboardPage)
+	})
+	mux.HandleFunc("/api/progress", d.serveProgress)
+	mux.HandleFunc("/api/runs", d.serveRuns)
+	mux.HandleFunc("/api/history", d.serveHistory)
+	return mux
+}
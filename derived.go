@@ -0,0 +1,172 @@
+package mclbench
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A DerivedMetric is sum(Num) / sum(Den) over recorded case names.
+// "{n}" in a name is expanded over every size recorded for that case, and
+// an "n*" prefix multiplies the term by that size.
+type DerivedMetric struct {
+	Name string
+	Num  []string
+	Den  []string
+}
+
+var derivedMetrics = []DerivedMetric{
+	{"G1MulVec{n}Speedup", []string{"n*G1Mul"}, []string{"G1MulVec{n}"}},
+	{"G2MulVec{n}Speedup", []string{"n*G2Mul"}, []string{"G2MulVec{n}"}},
+	{"MillerLoopVec{n}Speedup", []string{"n*MillerLoop"}, []string{"MillerLoopVec{n}"}},
+	{"MultiPairing{n}Speedup", []string{"n*Pairing"}, []string{"MultiPairing{n}"}},
+	{"MultiPairing{n}AvgOverPairing", []string{"MultiPairing{n}Avg"}, []string{"Pairing"}},
+	{"PairingOverMillerLoopFinalExp", []string{"Pairing"}, []string{"MillerLoop", "FinalExp"}},
+	{"FinalExpShareOfPairing", []string{"FinalExp"}, []string{"Pairing"}},
+	{"G1MulOverG1Add", []string{"G1Mul"}, []string{"G1Add"}},
+	{"FrSqrOverFrMul", []string{"FrSqr"}, []string{"FrMul"}},
+	{"FrDivOverFrInvFrMul", []string{"FrDiv"}, []string{"FrInv", "FrMul"}},
+	{"FrSquareRootOverFrInv", []string{"FrSquareRoot"}, []string{"FrInv"}},
+	{"FrModExpSlowOverFrPow", []string{"FrModExpSlow"}, []string{"FrPow"}},
+	{"G2MulOverG1Mul", []string{"G2Mul"}, []string{"G1Mul"}},
+	{"GTPowOverG1Mul", []string{"GTPow"}, []string{"G1Mul"}},
+	{"GTCyclotomicSqrSpeedup", []string{"GTMulSelf"}, []string{"GTCyclotomicSqr"}},
+	{"GTInvGenericOverUnitary", []string{"GTInvGeneric"}, []string{"GTInvUnitary"}},
+	{"GTPowSmallOverGTPow", []string{"GTPowSmall"}, []string{"GTPow"}},
+	{"GTMultiExp{n}SpeedupOverLoop", []string{"GTPowLoop{n}"}, []string{"GTMultiExp{n}"}},
+	{"GTDecompressOverGTMul", []string{"GTDecompress"}, []string{"GTMul"}},
+	{"PrecomputedMillerLoopSpeedup", []string{"MillerLoop"}, []string{"PrecomputedMillerLoop"}},
+	{"G1FixedBaseMul_w{n}Speedup", []string{"G1MulFixedPoint"}, []string{"G1FixedBaseMul_w{n}"}},
+	{"G2FixedBaseMul_w{n}Speedup", []string{"G2MulFixedPoint"}, []string{"G2FixedBaseMul_w{n}"}},
+	{"PrecomputedPairingSpeedup", []string{"Pairing"}, []string{"PrecomputedPairing"}},
+	{"PippengerG1{n}OverG1MulVec", []string{"PippengerG1{n}"}, []string{"G1MulVec{n}"}},
+	{"PippengerG1{n}SpeedupOverLoop", []string{"G1MulLoop{n}"}, []string{"PippengerG1{n}"}},
+	{"G1AddMixedSpeedup", []string{"G1AddJacobian"}, []string{"G1AddMixed"}},
+	{"G2AddMixedSpeedup", []string{"G2AddJacobian"}, []string{"G2AddMixed"}},
+	{"G1DblOverG1Add", []string{"G1Dbl"}, []string{"G1AddJacobian"}},
+	{"G1NormalizeBatch{n}Speedup", []string{"n*G1Normalize"}, []string{"G1NormalizeBatch{n}"}},
+	{"G2NormalizeBatch{n}Speedup", []string{"n*G2Normalize"}, []string{"G2NormalizeBatch{n}"}},
+	{"G1IsValidOrderOverG1Mul", []string{"G1IsValidOrder"}, []string{"G1Mul"}},
+	{"MLKZGOpen_l{n}OverCommit", []string{"MLKZGOpen_l{n}"}, []string{"MLKZGCommit_l{n}"}},
+	{"MLKZGVerify_l{n}OverPairing", []string{"MLKZGVerify_l{n}"}, []string{"Pairing"}},
+	{"PedersenVectorCommit{n}OverG1MulVec", []string{"PedersenVectorCommit{n}"}, []string{"G1MulVec{n}"}},
+	{"PedersenBatchVerify{n}Speedup", []string{"n*PedersenVerify"}, []string{"PedersenBatchVerify{n}"}},
+	{"PedersenGenerators{n}AvgOverG1Mul", []string{"PedersenGenerators{n}Avg"}, []string{"G1Mul"}},
+	{"IPAVerify_k{n}SpeedupOverNaive", []string{"IPAVerifyNaive_k{n}"}, []string{"IPAVerify_k{n}"}},
+	{"IPAProve_k{n}OverMLKZGOpen", []string{"IPAProve_k{n}"}, []string{"MLKZGOpen_l{n}"}},
+	{"IPAVerify_k{n}OverMLKZGVerify", []string{"IPAVerify_k{n}"}, []string{"MLKZGVerify_l{n}"}},
+}
+
+// reducers derive values other than ratios, such as the best of several
+// variants, before the ratios are computed.
+var reducers []func(db map[string]float64, out map[string]float64)
+
+// sizesOf returns the values of {n} for which template is a recorded case.
+func sizesOf(template string, db map[string]float64) []int {
+	re := regexp.MustCompile("^" + strings.Replace(regexp.QuoteMeta(template), `\{n\}`, `(\d+)`, 1) + "$")
+	var sizes []int
+	for k := range db {
+		if m := re.FindStringSubmatch(k); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			sizes = append(sizes, n)
+		}
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// sumTerms returns the sum of the terms for size n, or false if a case is missing.
+func sumTerms(terms []string, n int, db map[string]float64) (float64, bool) {
+	sum := 0.0
+	for _, t := range terms {
+		coef := 1.0
+		if strings.HasPrefix(t, "n*") {
+			coef = float64(n)
+			t = strings.TrimPrefix(t, "n*")
+		}
+		v, ok := db[strings.Replace(t, "{n}", strconv.Itoa(n), 1)]
+		if !ok {
+			return 0, false
+		}
+		sum += coef * v
+	}
+	return sum, true
+}
+
+func (m DerivedMetric) compute(db map[string]float64, out map[string]float64) {
+	sizes := []int{0}
+	for _, t := range append(append([]string{}, m.Num...), m.Den...) {
+		if strings.Contains(t, "{n}") {
+			sizes = sizesOf(strings.TrimPrefix(t, "n*"), db)
+			break
+		}
+	}
+	for _, n := range sizes {
+		num, ok1 := sumTerms(m.Num, n, db)
+		den, ok2 := sumTerms(m.Den, n, db)
+		if ok1 && ok2 && den != 0 {
+			out[strings.Replace(m.Name, "{n}", strconv.Itoa(n), 1)] = num / den
+		}
+	}
+}
+
+// procsSuffix matches keys recorded with a GOMAXPROCS value (see procsKey),
+// including their Avg and extra-metric forms: Name_p<N>, Name_p<N>Avg and
+// Name_p<N>_<unit>.
+var procsSuffix = regexp.MustCompile(`^(.+)_p(\d+)(Avg|_.+)?$`)
+
+// ProcsViews splits db by GOMAXPROCS value: view 0 holds the keys recorded
+// without one and view p the keys recorded with GOMAXPROCS p, with the _p<N>
+// suffix removed so that they read like a run with a single value.
+func ProcsViews(db map[string]float64) map[int]map[string]float64 {
+	views := make(map[int]map[string]float64)
+	for k, v := range db {
+		p := 0
+		if m := procsSuffix.FindStringSubmatch(k); m != nil {
+			p, _ = strconv.Atoi(m[2])
+			k = m[1] + m[3]
+		}
+		if views[p] == nil {
+			views[p] = make(map[string]float64)
+		}
+		views[p][k] = v
+	}
+	return views
+}
+
+// ComputeDerived evaluates every derived metric whose cases were recorded.
+// Results recorded with several GOMAXPROCS values get the metrics of each
+// value, as <metric>_p<N>.
+func ComputeDerived(db map[string]float64) map[string]float64 {
+	out := make(map[string]float64)
+	for p, view := range ProcsViews(db) {
+		for k, v := range computeDerived(view) {
+			if p != 0 {
+				k = procsKey(k, p)
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// computeDerived evaluates the derived metrics of a run with a single
+// GOMAXPROCS value.
+func computeDerived(db map[string]float64) map[string]float64 {
+	out := make(map[string]float64)
+	for _, r := range reducers {
+		r(db, out)
+	}
+	all := make(map[string]float64, len(db)+len(out))
+	for k, v := range db {
+		all[k] = v
+	}
+	for k, v := range out {
+		all[k] = v
+	}
+	for _, m := range derivedMetrics {
+		m.compute(all, out)
+	}
+	return out
+}
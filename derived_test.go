@@ -0,0 +1,30 @@
+package mclbench
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeDerivedPerProcs(t *testing.T) {
+	db := map[string]float64{
+		"G1Mul":           100,
+		"G1MulVec2":       150,
+		"G1MulVec2Avg":    75,
+		"G1Mul_p1":        100,
+		"G1Mul_p4":        100,
+		"G1MulVec2_p1":    160,
+		"G1MulVec2_p1Avg": 80,
+		"G1MulVec2_p4":    50,
+		"G1MulVec2_p4Avg": 25,
+		"G1MulVec2_p4_gc": 1,
+	}
+	views := ProcsViews(db)
+	if got, want := views[4], map[string]float64{"G1Mul": 100, "G1MulVec2": 50, "G1MulVec2Avg": 25, "G1MulVec2_gc": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("view 4 = %v, want %v", got, want)
+	}
+	got := ComputeDerived(db)
+	want := map[string]float64{"G1MulVec2Speedup": 200.0 / 150, "G1MulVec2Speedup_p1": 200.0 / 160, "G1MulVec2Speedup_p4": 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeDerived = %v, want %v", got, want)
+	}
+}
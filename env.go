@@ -0,0 +1,273 @@
+package mclbench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Environment describes the machine and build a run was recorded with.
+// Fields that cannot be read (e.g. no /proc or sysfs) are left empty.
+type Environment struct {
+	Host        string            `json:"host"`
+	Fingerprint string            `json:"fingerprint"`
+	OS          string            `json:"os"`
+	Arch        string            `json:"arch"`
+	Kernel      string            `json:"kernel,omitempty"`
+	CPUModel    string            `json:"cpu_model,omitempty"`
+	Cores       int               `json:"cores,omitempty"`
+	Threads     int               `json:"threads"`
+	Caches      []Cache           `json:"caches,omitempty"`
+	CPUFlags    []string          `json:"cpu_flags,omitempty"` // the ones mcl's assembly cares about
+	Governor    string            `json:"governor,omitempty"`
+	MemoryBytes uint64            `json:"memory_bytes,omitempty"`
+	GoVersion   string            `json:"go_version"`
+	MclVersion  string            `json:"mcl_version"`
+	BuildFlags  map[string]string `json:"build_flags,omitempty"`
+	Warnings    []string          `json:"warnings,omitempty"` // noisy conditions, see NoiseWarnings
+
+	// Run configuration that affects the numbers.
+	CPUs  []int `json:"pinned_cpus,omitempty"`
+	Procs []int `json:"gomaxprocs,omitempty"`
+	Seed  int64 `json:"seed,omitempty"`
+
+	GCDisabled bool `json:"gc_disabled,omitempty"`
+}
+
+type Cache struct {
+	Level int    `json:"level"`
+	Type  string `json:"type"` // Data, Instruction or Unified
+	Bytes int    `json:"bytes"`
+}
+
+func (c Cache) String() string {
+	kind := ""
+	if c.Type != "" && c.Type != "Unified" {
+		kind = strings.ToLower(c.Type[:1])
+	}
+	return fmt.Sprintf("L%d%s %dK", c.Level, kind, c.Bytes/1024)
+}
+
+// mclCPUFlags are the CPU features that select mcl's faster code paths.
+var mclCPUFlags = []string{"adx", "bmi2", "avx2", "avx512f", "avx512ifma"}
+
+func readTrimmed(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// cpuinfo returns the fields of the first processor in /proc/cpuinfo and the
+// number of processors and distinct physical cores.
+func cpuinfo() (fields map[string]string, threads, cores int) {
+	fields = make(map[string]string)
+	data, err := ioutil.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return fields, 0, 0
+	}
+	physical := make(map[string]bool)
+	var pkg string
+	for _, line := range strings.Split(string(data), "\n") {
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		k, v := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch k {
+		case "processor":
+			threads++
+		case "physical id":
+			pkg = v
+		case "core id":
+			physical[pkg+"/"+v] = true
+		}
+		if _, ok := fields[k]; !ok {
+			fields[k] = v
+		}
+	}
+	return fields, threads, len(physical)
+}
+
+// parseCacheSize parses sysfs sizes such as "48K" or "32M".
+func parseCacheSize(s string) int {
+	mult := 1
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1<<20, strings.TrimSuffix(s, "M")
+	}
+	n, _ := strconv.Atoi(s)
+	return n * mult
+}
+
+func caches() []Cache {
+	dirs, _ := filepath.Glob("/sys/devices/system/cpu/cpu0/cache/index*")
+	var out []Cache
+	for _, d := range dirs {
+		level, err := strconv.Atoi(readTrimmed(filepath.Join(d, "level")))
+		if err != nil {
+			continue
+		}
+		out = append(out, Cache{
+			Level: level,
+			Type:  readTrimmed(filepath.Join(d, "type")),
+			Bytes: parseCacheSize(readTrimmed(filepath.Join(d, "size"))),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Level != out[j].Level {
+			return out[i].Level < out[j].Level
+		}
+		return out[i].Type < out[j].Type
+	})
+	return out
+}
+
+func memTotal() uint64 {
+	data, err := ioutil.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if f := strings.Fields(line); len(f) >= 2 && f[0] == "MemTotal:" {
+			kb, _ := strconv.ParseUint(f[1], 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+func buildFlags() map[string]string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || len(info.Settings) == 0 {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, s := range info.Settings {
+		if s.Value != "" && s.Key != "DefaultGODEBUG" {
+			out[s.Key] = s.Value
+		}
+	}
+	return out
+}
+
+// CaptureEnvironment reads the environment of the current process.
+func CaptureEnvironment() *Environment {
+	host, _ := os.Hostname()
+	fields, threads, cores := cpuinfo()
+	if threads == 0 {
+		threads = runtime.NumCPU()
+	}
+	env := &Environment{
+		Host:        host,
+		Fingerprint: Fingerprint(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Kernel:      readTrimmed("/proc/sys/kernel/osrelease"),
+		CPUModel:    fields["model name"],
+		Cores:       cores,
+		Threads:     threads,
+		Caches:      caches(),
+		Governor:    readTrimmed("/sys/devices/system/cpu/cpu0/cpufreq/scaling_governor"),
+		MemoryBytes: memTotal(),
+		GoVersion:   runtime.Version(),
+		MclVersion:  MclVersion(),
+		BuildFlags:  buildFlags(),
+	}
+	have := make(map[string]bool)
+	for _, f := range strings.Fields(fields["flags"]) {
+		have[f] = true
+	}
+	for _, f := range mclCPUFlags {
+		if have[f] {
+			env.CPUFlags = append(env.CPUFlags, f)
+		}
+	}
+	return env
+}
+
+func orNA(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
+
+// Lines is the environment as "key: value" lines for run headers.
+func (e *Environment) Lines() []string {
+	var caches []string
+	for _, c := range e.Caches {
+		caches = append(caches, c.String())
+	}
+	lines := []string{
+		"Host: " + e.Host + " (" + e.Fingerprint + ")",
+		fmt.Sprintf("CPU: %s, %d cores, %d threads", e.CPUModel, e.Cores, e.Threads),
+		"Caches: " + strings.Join(caches, ", "),
+		"CPU flags: " + strings.Join(e.CPUFlags, ", "),
+		"Governor: " + orNA(e.Governor),
+		fmt.Sprintf("Memory: %.1f GiB", float64(e.MemoryBytes)/(1<<30)),
+		fmt.Sprintf("OS: %s/%s, kernel %s", e.OS, e.Arch, e.Kernel),
+		"Go: " + e.GoVersion + ", go-mcl " + e.MclVersion,
+	}
+	keys := make([]string, 0, len(e.BuildFlags))
+	for k := range e.BuildFlags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var flags []string
+	for _, k := range keys {
+		flags = append(flags, k+"="+e.BuildFlags[k])
+	}
+	if len(flags) > 0 {
+		lines = append(lines, "Build: "+strings.Join(flags, " "))
+	}
+	if len(e.CPUs) > 0 {
+		lines = append(lines, "Pinned CPUs: "+joinInts(e.CPUs))
+	}
+	if len(e.Procs) > 0 {
+		lines = append(lines, "GOMAXPROCS: "+joinInts(e.Procs))
+	}
+	if e.Seed != 0 {
+		lines = append(lines, fmt.Sprintf("Fixture seed: %d", e.Seed))
+	}
+	if e.GCDisabled {
+		lines = append(lines, "GC: disabled while timing")
+	}
+	if len(e.Warnings) > 0 {
+		lines = append(lines, "Warnings: "+strings.Join(e.Warnings, "; "))
+	}
+	return lines
+}
+
+// EnvPath is the file next to a results file that holds its environment.
+func EnvPath(results string) string {
+	return strings.TrimSuffix(results, filepath.Ext(results)) + ".env.json"
+}
+
+func SaveEnvironment(path string, env *Environment) error {
+	json, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, json, 0666)
+}
+
+func LoadEnvironment(path string) (*Environment, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var env Environment
+	err = json.Unmarshal(data, &env)
+	return &env, err
+}
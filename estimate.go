@@ -0,0 +1,471 @@
+package mclbench
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Protocol cost estimates from named formulas over recorded primitive costs:
+//
+//	public_inputs = 10
+//	groth16_verify = 3*Pairing + MultiPairing3 + G1MulVec(public_inputs)
+//	commit = G1MulVec(2^ell)
+//	open = sum(i, 1, ell, G1MulVec(2^(ell-i)))
+//
+// Names resolve to earlier formulas, then to results keys. Case(n) and keys
+// like MultiPairing3 that were never recorded fall back to the fitted curve
+// of the swept case (see fit.go), carrying its 95% prediction bound.
+
+// Estimate is a cost in ns with the half-width of its error bound.
+type Estimate struct {
+	Ns    float64
+	Bound float64
+}
+
+type exprNode interface {
+	eval(e *Estimator) (Estimate, error)
+	String() string
+}
+
+type numNode float64
+
+type identNode string
+
+type callNode struct {
+	name string
+	arg  exprNode
+}
+
+type binNode struct {
+	op   byte
+	l, r exprNode
+}
+
+type negNode struct{ x exprNode }
+
+// sumNode is sum(v, lo, hi, body): body summed with v bound to lo, ..., hi.
+type sumNode struct {
+	v      string
+	lo, hi exprNode
+	body   exprNode
+}
+
+func (n numNode) String() string   { return strconv.FormatFloat(float64(n), 'g', -1, 64) }
+func (n identNode) String() string { return string(n) }
+func (n callNode) String() string  { return n.name + "(" + n.arg.String() + ")" }
+func (n binNode) String() string   { return "(" + n.l.String() + string(n.op) + n.r.String() + ")" }
+func (n negNode) String() string   { return "-" + n.x.String() }
+func (n sumNode) String() string {
+	return "sum(" + n.v + ", " + n.lo.String() + ", " + n.hi.String() + ", " + n.body.String() + ")"
+}
+
+func (n numNode) eval(e *Estimator) (Estimate, error) { return Estimate{Ns: float64(n)}, nil }
+
+func (n identNode) eval(e *Estimator) (Estimate, error) { return e.lookup(string(n)) }
+
+func (n callNode) eval(e *Estimator) (Estimate, error) {
+	arg, err := n.arg.eval(e)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return e.lookupSized(n.name, arg.Ns)
+}
+
+func (n negNode) eval(e *Estimator) (Estimate, error) {
+	x, err := n.x.eval(e)
+	return Estimate{-x.Ns, x.Bound}, err
+}
+
+func (n sumNode) eval(e *Estimator) (Estimate, error) {
+	lo, err := n.lo.eval(e)
+	if err != nil {
+		return Estimate{}, err
+	}
+	hi, err := n.hi.eval(e)
+	if err != nil {
+		return Estimate{}, err
+	}
+	if lo.Ns != math.Trunc(lo.Ns) || hi.Ns != math.Trunc(hi.Ns) || lo.Bound != 0 || hi.Bound != 0 {
+		return Estimate{}, fmt.Errorf("bounds of %s must be exact integers", n)
+	}
+	prev, shadowed := e.bound[n.v]
+	defer func() {
+		if shadowed {
+			e.bound[n.v] = prev
+		} else {
+			delete(e.bound, n.v)
+		}
+	}()
+	var sum Estimate
+	for i := lo.Ns; i <= hi.Ns; i++ {
+		e.bound[n.v] = i
+		x, err := n.body.eval(e)
+		if err != nil {
+			return Estimate{}, err
+		}
+		sum.Ns += x.Ns
+		sum.Bound += x.Bound
+	}
+	return sum, nil
+}
+
+func (n binNode) eval(e *Estimator) (Estimate, error) {
+	l, err := n.l.eval(e)
+	if err != nil {
+		return Estimate{}, err
+	}
+	r, err := n.r.eval(e)
+	if err != nil {
+		return Estimate{}, err
+	}
+	switch n.op {
+	case '+':
+		return Estimate{l.Ns + r.Ns, l.Bound + r.Bound}, nil
+	case '-':
+		return Estimate{l.Ns - r.Ns, l.Bound + r.Bound}, nil
+	case '*':
+		return Estimate{l.Ns * r.Ns, scaled(l.Ns, r.Bound) + scaled(r.Ns, l.Bound)}, nil
+	case '^':
+		if r.Bound != 0 {
+			return Estimate{}, fmt.Errorf("exponent of %s must be exact", n)
+		}
+		v := math.Pow(l.Ns, r.Ns)
+		return Estimate{v, scaled(r.Ns*v/l.Ns, l.Bound)}, nil
+	}
+	if r.Ns == 0 {
+		return Estimate{}, fmt.Errorf("division by zero in %s", n)
+	}
+	return Estimate{l.Ns / r.Ns, (l.Bound + scaled(l.Ns/r.Ns, r.Bound)) / math.Abs(r.Ns)}, nil
+}
+
+// scaled is |x| * bound, with an unbounded (+Inf) bound times zero being zero.
+func scaled(x, bound float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return math.Abs(x) * bound
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) skip() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skip()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '.' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// terms parses a sum and returns its top-level terms with their signs and
+// source text.
+func (p *parser) terms() ([]exprNode, []string, error) {
+	var out []exprNode
+	var text []string
+	neg := false
+	for {
+		start := p.pos
+		t, err := p.term()
+		if err != nil {
+			return nil, nil, err
+		}
+		label := strings.TrimSpace(p.s[start:p.pos])
+		if neg {
+			t = negNode{t}
+			label = "-" + label
+		}
+		out = append(out, t)
+		text = append(text, label)
+		switch p.peek() {
+		case '+':
+			neg = false
+		case '-':
+			neg = true
+		default:
+			return out, text, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) expr() (exprNode, error) {
+	ts, _, err := p.terms()
+	if err != nil {
+		return nil, err
+	}
+	return sumNodes(ts), nil
+}
+
+func sumNodes(ts []exprNode) exprNode {
+	n := ts[0]
+	for _, t := range ts[1:] {
+		n = binNode{'+', n, t}
+	}
+	return n
+}
+
+func (p *parser) term() (exprNode, error) {
+	n, err := p.power()
+	if err != nil {
+		return nil, err
+	}
+	for c := p.peek(); c == '*' || c == '/'; c = p.peek() {
+		p.pos++
+		r, err := p.power()
+		if err != nil {
+			return nil, err
+		}
+		n = binNode{c, n, r}
+	}
+	return n, nil
+}
+
+// power parses a factor with an optional right-associative ^ exponent.
+func (p *parser) power() (exprNode, error) {
+	n, err := p.factor()
+	if err != nil || p.peek() != '^' {
+		return n, err
+	}
+	p.pos++
+	r, err := p.power()
+	if err != nil {
+		return nil, err
+	}
+	return binNode{'^', n, r}, nil
+}
+
+func (p *parser) factor() (exprNode, error) {
+	c := p.peek()
+	switch {
+	case c == '-':
+		p.pos++
+		x, err := p.factor()
+		return negNode{x}, err
+	case c == '(':
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ) at %d in %q", p.pos, p.s)
+		}
+		p.pos++
+		return x, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.s) && (strings.IndexByte("0123456789.eE", p.s[p.pos]) >= 0 ||
+			(p.s[p.pos] == '-' || p.s[p.pos] == '+') && (p.s[p.pos-1] == 'e' || p.s[p.pos-1] == 'E')) {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		return numNode(v), err
+	case isIdent(c):
+		start := p.pos
+		for p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		if name == "sum" && p.peek() == '(' {
+			return p.sum()
+		}
+		if p.peek() == '(' {
+			p.pos++
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != ')' {
+				return nil, fmt.Errorf("expected ) at %d in %q", p.pos, p.s)
+			}
+			p.pos++
+			return callNode{name, arg}, nil
+		}
+		return identNode(name), nil
+	}
+	return nil, fmt.Errorf("unexpected %q at %d in %q", c, p.pos, p.s)
+}
+
+// sum parses the arguments of sum(v, lo, hi, body) after its name.
+func (p *parser) sum() (exprNode, error) {
+	p.pos++
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+		p.pos++
+	}
+	n := sumNode{v: p.s[start:p.pos]}
+	if n.v == "" {
+		return nil, fmt.Errorf("expected a variable at %d in %q", p.pos, p.s)
+	}
+	for _, arg := range []*exprNode{&n.lo, &n.hi, &n.body} {
+		if p.peek() != ',' {
+			return nil, fmt.Errorf("expected , at %d in %q", p.pos, p.s)
+		}
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		*arg = x
+	}
+	if p.peek() != ')' {
+		return nil, fmt.Errorf("expected ) at %d in %q", p.pos, p.s)
+	}
+	p.pos++
+	return n, nil
+}
+
+// A Formula is one "name = term + term ..." line of a formula file.
+type Formula struct {
+	Name   string
+	Labels []string // source text of each top-level term
+	terms  []exprNode
+}
+
+// IsConstant reports whether the formula is a plain number, i.e. a variable.
+func (f *Formula) IsConstant() bool {
+	_, ok := f.terms[0].(numNode)
+	return ok && len(f.terms) == 1
+}
+
+type Estimator struct {
+	db       map[string]float64
+	formulas map[string]*Formula
+	vars     map[string]float64
+	bound    map[string]float64 // variables of the enclosing sum() terms
+	fits     map[string]Fit
+	active   map[string]bool
+}
+
+// NewEstimator evaluates formulas against the results db. vars override
+// formulas of the same name.
+func NewEstimator(db map[string]float64, formulas []*Formula, vars map[string]float64) *Estimator {
+	e := &Estimator{db: db, formulas: make(map[string]*Formula), vars: vars, bound: make(map[string]float64), fits: make(map[string]Fit), active: make(map[string]bool)}
+	for _, f := range formulas {
+		e.formulas[f.Name] = f
+	}
+	return e
+}
+
+// Estimate returns the cost of a formula, variable or results key.
+func (e *Estimator) Estimate(name string) (Estimate, error) {
+	return e.lookup(name)
+}
+
+// Breakdown evaluates each top-level term of f separately.
+func (e *Estimator) Breakdown(f *Formula) ([]Estimate, []error) {
+	vals := make([]Estimate, len(f.terms))
+	errs := make([]error, len(f.terms))
+	for i, t := range f.terms {
+		vals[i], errs[i] = t.eval(e)
+	}
+	return vals, errs
+}
+
+func (e *Estimator) fit(name string) (Fit, error) {
+	if f, ok := e.fits[name]; ok {
+		return f, nil
+	}
+	m, _ := FitModelByName(DefaultModel(name))
+	f, err := FitCase(e.db, name, m)
+	if err != nil {
+		return Fit{}, err
+	}
+	e.fits[name] = f
+	return f, nil
+}
+
+// lookupSized returns the cost of case at size n, exact if recorded, fitted otherwise.
+func (e *Estimator) lookupSized(name string, n float64) (Estimate, error) {
+	if n < 1 {
+		return Estimate{}, fmt.Errorf("%s(%g): size must be at least 1", name, n)
+	}
+	if n == math.Trunc(n) {
+		if v, ok := e.db[sizedName(name, int(n))]; ok {
+			return Estimate{Ns: v}, nil
+		}
+	}
+	f, err := e.fit(name)
+	if err != nil {
+		return Estimate{}, err
+	}
+	v, bound := f.Predict(n)
+	return Estimate{v, bound}, nil
+}
+
+func (e *Estimator) lookup(name string) (Estimate, error) {
+	if v, ok := e.bound[name]; ok {
+		return Estimate{Ns: v}, nil
+	}
+	if v, ok := e.vars[name]; ok {
+		return Estimate{Ns: v}, nil
+	}
+	if f, ok := e.formulas[name]; ok {
+		if e.active[name] {
+			return Estimate{}, fmt.Errorf("%s is defined in terms of itself", name)
+		}
+		e.active[name] = true
+		defer delete(e.active, name)
+		return sumNodes(f.terms).eval(e)
+	}
+	if v, ok := e.db[name]; ok {
+		return Estimate{Ns: v}, nil
+	}
+	if c, n, ok := splitSizedKey(sizedCases(), name); ok {
+		return e.lookupSized(c, float64(n))
+	}
+	return Estimate{}, fmt.Errorf("unknown name %s", name)
+}
+
+// ParseFormulas reads a formula file: one "name = formula" per line, # comments.
+func ParseFormulas(path string) ([]*Formula, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var out []*Formula
+	sc := bufio.NewScanner(fd)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		eq := strings.IndexByte(text, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("%s:%d: expected name = formula", path, line)
+		}
+		p := &parser{s: text[eq+1:]}
+		ts, labels, err := p.terms()
+		if err == nil && p.peek() != 0 {
+			err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		out = append(out, &Formula{Name: strings.TrimSpace(text[:eq]), Labels: labels, terms: ts})
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,45 @@
+package mclbench
+
+import "testing"
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		tree string
+		want float64
+	}{
+		{"1 + 2*3", "(1+(2*3))", 7},
+		{"(1 + 2)*3", "((1+2)*3)", 9},
+		{"8/4/2", "((8/4)/2)", 1},
+		{"1 - 2 - 3", "((1+-2)+-3)", -4},
+		{"2*3^2", "(2*(3^2))", 18},
+		{"2^3^2", "(2^(3^2))", 512},
+		{"(2^3)^2", "((2^3)^2)", 64},
+		{"2^-1", "(2^-1)", 0.5},
+		{"-2*3", "(-2*3)", -6},
+		{"2*-3", "(2*-3)", -6},
+		{"1 - -1", "(1+--1)", 2},
+		{"-(1 + 2)", "-(1+2)", -3},
+		{"x*2^x", "(x*(2^x))", 24},
+		{"sum(i, 1, x, i^2)", "sum(i, 1, x, (i^2))", 14},
+		{"sum(i, 2, 1, i)", "sum(i, 2, 1, i)", 0},
+	}
+	e := NewEstimator(nil, nil, map[string]float64{"x": 3})
+	for _, tt := range tests {
+		p := &parser{s: tt.expr}
+		n, err := p.expr()
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if p.peek() != 0 {
+			t.Errorf("%s: trailing input at %d", tt.expr, p.pos)
+		}
+		if got := n.String(); got != tt.tree {
+			t.Errorf("%s parsed as %s, want %s", tt.expr, got, tt.tree)
+		}
+		if got, err := n.eval(e); err != nil || got.Ns != tt.want {
+			t.Errorf("%s = %v, %v, want %v", tt.expr, got.Ns, err, tt.want)
+		}
+	}
+}
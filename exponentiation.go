@@ -0,0 +1,456 @@
+package mclbench
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func registerExponentiation() {
+
+	Register(Case{Name: "G1Neg", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Neg(&result, &fx.G1[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1Add", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Add(&result, &result, &fx.G1[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1Sub", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Sub(&result, &result, &fx.G1[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1Mul", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Mul(&result, &fx.G1[j], &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1MulVec", Group: "G1", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			mcl.G1MulVec(&result, fx.G1[:n], fx.Fr[:n])
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1Dbl", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Dbl(&result, &fx.G1[j])
+			}
+		}
+	}})
+	// =============================================
+	// Both operands in Jacobian coordinates.
+	Register(Case{Name: "G1AddJacobian", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		jac := jacobianG1(fx.G1[:n])
+		mcl.G1Dbl(&result, &fx.G1[0])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Add(&result, &result, &jac[j])
+			}
+		}
+	}})
+	// =============================================
+	// One normalized operand (Z = 1), which mcl adds with mixed addition.
+	Register(Case{Name: "G1AddMixed", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		aff := affineG1(fx.G1[:n])
+		mcl.G1Dbl(&result, &fx.G1[0])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Add(&result, &result, &aff[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1Normalize", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		jac := jacobianG1(fx.G1[:n])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Normalize(&result, &jac[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1NormalizeBatch", Group: "G1", Vector: true, Unit: "point", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		jac := jacobianG1(fx.G1[:n])
+		if err := checkNormalizeG1Batch(jac); err != nil {
+			t.Fatal(err)
+		}
+		result := make([]mcl.G1, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			NormalizeG1Batch(result, jac)
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1IsValid", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G1[j].IsValid()
+			}
+		}
+	}})
+	// =============================================
+	// Subgroup membership, the check needed on deserialized points.
+	Register(Case{Name: "G1IsValidOrder", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G1[j].IsValidOrder()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1IsZero", Group: "G1", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G1[j].IsZero()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Neg", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Neg(&result, &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Add", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Add(&result, &result, &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Sub", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Sub(&result, &result, &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Mul", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Mul(&result, &fx.G2[j], &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2MulVec", Group: "G2", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			mcl.G2MulVec(&result, fx.G2[:n], fx.Fr[:n])
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Dbl", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Dbl(&result, &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2AddJacobian", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		jac := jacobianG2(fx.G2[:n])
+		mcl.G2Dbl(&result, &fx.G2[0])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Add(&result, &result, &jac[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2AddMixed", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		aff := affineG2(fx.G2[:n])
+		mcl.G2Dbl(&result, &fx.G2[0])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Add(&result, &result, &aff[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2Normalize", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		jac := jacobianG2(fx.G2[:n])
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Normalize(&result, &jac[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2NormalizeBatch", Group: "G2", Vector: true, Unit: "point", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		jac := jacobianG2(fx.G2[:n])
+		if err := checkNormalizeG2Batch(jac); err != nil {
+			t.Fatal(err)
+		}
+		result := make([]mcl.G2, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			NormalizeG2Batch(result, jac)
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2IsValid", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G2[j].IsValid()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2IsValidOrder", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G2[j].IsValidOrder()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2IsZero", Group: "G2", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.G2[j].IsZero()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrNeg", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrNeg(&result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrInv", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrInv(&result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrAdd", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrAdd(&result, &result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrSub", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrSub(&result, &result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrMul", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			mcl.FrMul(&result, &result, &fx.Fr[0])
+			for j := 1; j < n; j++ {
+				mcl.FrMul(&result, &fx.Fr[j-1], &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrCopy", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			dst := make([]mcl.Fr, n)
+			for j := 0; j < n; j++ {
+				dst[j] = fx.Fr[j]
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrSqr", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrSqr(&result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrDiv", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrDiv(&result, &result, &fx.Fr[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrSquareRoot", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		squares := make([]mcl.Fr, n)
+		for j := range squares {
+			mcl.FrSqr(&squares[j], &fx.Fr[j])
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrSquareRoot(&result, &squares[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrLegendre", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		half := frOrder()
+		half.Rsh(half, 1)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				FrLegendre(&fx.Fr[j], half)
+			}
+		}
+	}})
+	// =============================================
+	// Full-width exponents, by square-and-multiply in Go over FrSqr and FrMul.
+	Register(Case{Name: "FrPow", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		exps := make([]*big.Int, n)
+		for j := range exps {
+			exps[j] = fx.Fr[(j+1)%n].ToBigInt()
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				FrPow(&result, &fx.Fr[j], exps[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrModExpSlow", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FrModExp_Slow(&fx.Fr[j], &fx.Fr[(j+1)%n])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrToBigInt", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				fx.Fr[j].ToBigInt()
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrFromBigInt", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		ints := make([]*big.Int, n)
+		for j := range ints {
+			ints[j] = fx.Fr[j].ToBigInt()
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.BigIntToFr(ints[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrSetHashOf", Group: "Fr", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.Fr
+		msgs := make([][]byte, n)
+		for j := range msgs {
+			msgs[j] = fx.Fr[j].Serialize()
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				result.SetHashOf(msgs[j])
+			}
+		}
+	}})
+}
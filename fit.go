@@ -0,0 +1,227 @@
+package mclbench
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Least-squares fits of size-swept cases (keys like G1MulVec32) to
+// y = a*f(n) + b, used to extrapolate to sizes that were never run.
+
+type FitModel struct {
+	Name    string
+	Formula string
+	f       func(n float64) float64
+}
+
+var FitModels = []FitModel{
+	{"linear", "a*n + b", func(n float64) float64 { return n }},
+	{"msm", "a*n/log2(n) + b", func(n float64) float64 { return n / math.Max(1, math.Log2(n)) }},
+	{"nlogn", "a*n*log2(n) + b", func(n float64) float64 { return n * math.Log2(n) }},
+}
+
+func FitModelByName(name string) (FitModel, error) {
+	for _, m := range FitModels {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return FitModel{}, fmt.Errorf("unknown model %q", name)
+}
+
+// DefaultModel picks the cost model we expect for a case.
+func DefaultModel(name string) string {
+	if strings.Contains(name, "MulVec") || strings.HasPrefix(name, "Pippenger") {
+		return "msm"
+	}
+	return "linear"
+}
+
+type Fit struct {
+	Case   string  `json:"case"`
+	Model  string  `json:"model"`
+	A      float64 `json:"a"`
+	B      float64 `json:"b"`
+	R2     float64 `json:"r2"`
+	Points int     `json:"points"`
+
+	f     func(n float64) float64
+	xMean float64
+	sxx   float64
+	s     float64 // residual standard error
+}
+
+// tQuantile975 is the two-sided 95% Student t critical value for dof degrees
+// of freedom. Above 30 it rounds dof down to the next tabulated value, which
+// errs on the side of wider intervals.
+func tQuantile975(dof int) float64 {
+	table := []float64{
+		12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+		2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+		2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+	}
+	switch {
+	case dof < 1:
+		return math.Inf(1)
+	case dof <= len(table):
+		return table[dof-1]
+	case dof < 40:
+		return 2.042
+	case dof < 60:
+		return 2.021
+	case dof < 120:
+		return 2.000
+	}
+	return 1.980
+}
+
+// FitCase fits the recorded sizes of the vector case name to model m.
+// Sizes below 2 are left out, where n/log2(n) is not meaningful.
+func FitCase(db map[string]float64, name string, m FitModel) (Fit, error) {
+	var xs, ys []float64
+	for _, n := range sizesOf(sizeTemplate(name), db) {
+		if n < 2 {
+			continue
+		}
+		xs = append(xs, m.f(float64(n)))
+		ys = append(ys, db[sizedName(name, n)])
+	}
+	k := float64(len(xs))
+	if len(xs) < 2 {
+		return Fit{}, fmt.Errorf("%s: need at least 2 sizes, have %d", name, len(xs))
+	}
+
+	var xMean, yMean float64
+	for i := range xs {
+		xMean += xs[i] / k
+		yMean += ys[i] / k
+	}
+	var sxx, sxy, syy float64
+	for i := range xs {
+		sxx += (xs[i] - xMean) * (xs[i] - xMean)
+		sxy += (xs[i] - xMean) * (ys[i] - yMean)
+		syy += (ys[i] - yMean) * (ys[i] - yMean)
+	}
+	fit := Fit{Case: name, Model: m.Name, Points: len(xs), f: m.f, xMean: xMean, sxx: sxx}
+	fit.A = sxy / sxx
+	fit.B = yMean - fit.A*xMean
+
+	var ssr float64
+	for i := range xs {
+		r := ys[i] - (fit.A*xs[i] + fit.B)
+		ssr += r * r
+	}
+	fit.R2 = 1
+	if syy > 0 {
+		fit.R2 = 1 - ssr/syy
+	}
+	if len(xs) > 2 {
+		fit.s = math.Sqrt(ssr / (k - 2))
+	}
+	return fit, nil
+}
+
+// Predict returns the fitted cost at n and the half-width of its 95% prediction
+// interval, which is +Inf for a fit through two points.
+func (f Fit) Predict(n float64) (float64, float64) {
+	x := f.f(n)
+	if f.Points < 3 {
+		return f.A*x + f.B, math.Inf(1)
+	}
+	k := float64(f.Points)
+	se := f.s * math.Sqrt(1+1/k+(x-f.xMean)*(x-f.xMean)/f.sxx)
+	return f.A*x + f.B, tQuantile975(f.Points-2) * se
+}
+
+// sizeTemplate is name with "{n}" where the size goes, as in Case.Key.
+func sizeTemplate(name string) string {
+	if strings.Contains(name, "{n}") {
+		return name
+	}
+	return name + "{n}"
+}
+
+// sizedName is the results key of the vector case name at size n.
+func sizedName(name string, n int) string {
+	return strings.Replace(sizeTemplate(name), "{n}", strconv.Itoa(n), 1)
+}
+
+type sizedCase struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// sizedCases returns a matcher for the keys of every registered vector case.
+func sizedCases() []sizedCase {
+	var out []sizedCase
+	for _, c := range registry {
+		if !c.Vector {
+			continue
+		}
+		t := regexp.QuoteMeta(sizeTemplate(c.Name))
+		re := regexp.MustCompile("^" + strings.Replace(t, `\{n\}`, `(\d+)`, 1) + "$")
+		out = append(out, sizedCase{c.Name, re})
+	}
+	return out
+}
+
+// splitSizedKey splits a key like G1MulVec32 into the registered vector case
+// and its size. Matching registered names rather than the digits at the end
+// keeps names such as PippengerG1 whole.
+func splitSizedKey(cases []sizedCase, key string) (string, int, bool) {
+	for _, c := range cases {
+		if m := c.re.FindStringSubmatch(key); m != nil {
+			n, err := strconv.Atoi(m[1])
+			return c.name, n, err == nil
+		}
+	}
+	return "", 0, false
+}
+
+// SweptCases returns the vector cases recorded at three or more sizes.
+func SweptCases(db map[string]float64) []string {
+	cases := sizedCases()
+	count := make(map[string]int)
+	for k := range db {
+		if name, _, ok := splitSizedKey(cases, k); ok {
+			count[name]++
+		}
+	}
+	var names []string
+	for name, c := range count {
+		if c >= 3 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSizes parses a comma-separated list of sizes; 2^k is allowed.
+func ParseSizes(s string) ([]float64, error) {
+	var sizes []float64
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if strings.HasPrefix(f, "2^") {
+			e, err := strconv.Atoi(f[2:])
+			if err != nil {
+				return nil, err
+			}
+			sizes = append(sizes, math.Pow(2, float64(e)))
+			continue
+		}
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, v)
+	}
+	return sizes, nil
+}
@@ -0,0 +1,222 @@
+package mclbench
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Fixed-base scalar multiplication with a windowed precomputed table.
+// For window size w, table[i][d-1] = d * 2^(w*i) * P for d in [1, 2^w), so a
+// multiplication is one (mixed) addition per window and no doublings.
+
+// scalarDigit returns the i-th w-bit digit of the little-endian scalar k.
+func scalarDigit(k []byte, i int, w int) int {
+	d := 0
+	for b := 0; b < w; b++ {
+		bit := i*w + b
+		if bit/8 >= len(k) {
+			break
+		}
+		d |= int(k[bit/8]>>uint(bit%8)&1) << uint(b)
+	}
+	return d
+}
+
+// scalarDigits returns the w-bit digits of k, least significant first.
+// Recoding goes through k.Serialize, which allocates, so the timed cases
+// recode their scalars up front and call MulDigits.
+func scalarDigits(k *mcl.Fr, w int) []int {
+	buf := k.Serialize()
+	digits := make([]int, numWindows(w))
+	for i := range digits {
+		digits[i] = scalarDigit(buf, i, w)
+	}
+	return digits
+}
+
+func numWindows(w int) int {
+	bits := mcl.GetFrByteSize() * 8
+	return (bits + w - 1) / w
+}
+
+type G1Table struct {
+	w     int
+	table [][]mcl.G1
+}
+
+func NewG1Table(P *mcl.G1, w int) *G1Table {
+	t := &G1Table{w: w, table: make([][]mcl.G1, numWindows(w))}
+	base := *P
+	for i := range t.table {
+		row := make([]mcl.G1, (1<<uint(w))-1)
+		row[0] = base
+		for d := 1; d < len(row); d++ {
+			mcl.G1Add(&row[d], &row[d-1], &base)
+		}
+		for d := range row {
+			mcl.G1Normalize(&row[d], &row[d])
+		}
+		t.table[i] = row
+		for b := 0; b < w; b++ {
+			mcl.G1Dbl(&base, &base)
+		}
+	}
+	return t
+}
+
+func (t *G1Table) Mul(out *mcl.G1, k *mcl.Fr) {
+	t.MulDigits(out, t.Digits(k))
+}
+
+// Digits recodes k for MulDigits.
+func (t *G1Table) Digits(k *mcl.Fr) []int {
+	return scalarDigits(k, t.w)
+}
+
+// MulDigits sets out to the product of the table's point and the scalar
+// recoded by Digits.
+func (t *G1Table) MulDigits(out *mcl.G1, digits []int) {
+	out.Clear()
+	for i, d := range digits {
+		if d != 0 {
+			mcl.G1Add(out, out, &t.table[i][d-1])
+		}
+	}
+}
+
+func (t *G1Table) Bytes() uint64 {
+	return uint64(len(t.table)) * uint64(len(t.table[0])) * uint64(unsafe.Sizeof(mcl.G1{}))
+}
+
+type G2Table struct {
+	w     int
+	table [][]mcl.G2
+}
+
+func NewG2Table(Q *mcl.G2, w int) *G2Table {
+	t := &G2Table{w: w, table: make([][]mcl.G2, numWindows(w))}
+	base := *Q
+	for i := range t.table {
+		row := make([]mcl.G2, (1<<uint(w))-1)
+		row[0] = base
+		for d := 1; d < len(row); d++ {
+			mcl.G2Add(&row[d], &row[d-1], &base)
+		}
+		for d := range row {
+			mcl.G2Normalize(&row[d], &row[d])
+		}
+		t.table[i] = row
+		for b := 0; b < w; b++ {
+			mcl.G2Dbl(&base, &base)
+		}
+	}
+	return t
+}
+
+func (t *G2Table) Mul(out *mcl.G2, k *mcl.Fr) {
+	t.MulDigits(out, t.Digits(k))
+}
+
+// Digits recodes k for MulDigits.
+func (t *G2Table) Digits(k *mcl.Fr) []int {
+	return scalarDigits(k, t.w)
+}
+
+// MulDigits sets out to the product of the table's point and the scalar
+// recoded by Digits.
+func (t *G2Table) MulDigits(out *mcl.G2, digits []int) {
+	out.Clear()
+	for i, d := range digits {
+		if d != 0 {
+			mcl.G2Add(out, out, &t.table[i][d-1])
+		}
+	}
+}
+
+func (t *G2Table) Bytes() uint64 {
+	return uint64(len(t.table)) * uint64(len(t.table[0])) * uint64(unsafe.Sizeof(mcl.G2{}))
+}
+
+func registerFixedBase() {
+
+	windows := []int{2, 4, 6, 8, 10}
+
+	Register(Case{Name: "G1MulFixedPoint", Group: "FixedBase", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Mul(&result, &fx.G1[0], &fx.Fr[j])
+			}
+		}
+	}})
+	for _, w := range windows {
+		w := w
+		Register(Case{Name: fmt.Sprintf("G1FixedBaseBuild_w%d", w), Group: "FixedBase", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			for i := 0; i < t.N; i++ {
+				NewG1Table(&fx.G1[0], w)
+			}
+		}})
+		Register(Case{Name: fmt.Sprintf("G1FixedBaseMul_w%d", w), Group: "FixedBase", Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var result, want mcl.G1
+			table := NewG1Table(&fx.G1[0], w)
+			table.Mul(&result, &fx.Fr[0])
+			mcl.G1Mul(&want, &fx.G1[0], &fx.Fr[0])
+			if !result.IsEqual(&want) {
+				t.Fatalf("w=%d table product differs from G1Mul", w)
+			}
+			digits := make([][]int, n)
+			for j := range digits {
+				digits[j] = table.Digits(&fx.Fr[j])
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				for j := 0; j < n; j++ {
+					table.MulDigits(&result, digits[j])
+				}
+			}
+			t.ReportMetric(float64(table.Bytes()), "bytes")
+		}})
+	}
+	// =============================================
+	Register(Case{Name: "G2MulFixedPoint", Group: "FixedBase", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G2
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G2Mul(&result, &fx.G2[0], &fx.Fr[j])
+			}
+		}
+	}})
+	for _, w := range windows {
+		w := w
+		Register(Case{Name: fmt.Sprintf("G2FixedBaseBuild_w%d", w), Group: "FixedBase", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			for i := 0; i < t.N; i++ {
+				NewG2Table(&fx.G2[0], w)
+			}
+		}})
+		Register(Case{Name: fmt.Sprintf("G2FixedBaseMul_w%d", w), Group: "FixedBase", Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var result, want mcl.G2
+			table := NewG2Table(&fx.G2[0], w)
+			table.Mul(&result, &fx.Fr[0])
+			mcl.G2Mul(&want, &fx.G2[0], &fx.Fr[0])
+			if !result.IsEqual(&want) {
+				t.Fatalf("w=%d table product differs from G2Mul", w)
+			}
+			digits := make([][]int, n)
+			for j := range digits {
+				digits[j] = table.Digits(&fx.Fr[j])
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				for j := 0; j < n; j++ {
+					table.MulDigits(&result, digits[j])
+				}
+			}
+			t.ReportMetric(float64(table.Bytes()), "bytes")
+		}})
+	}
+}
@@ -0,0 +1,57 @@
+package mclbench
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// fixedBaseScalars returns random scalars and the edge cases of window size
+// w: 0, 1, r-1, the largest lowest digit and the largest digit in the highest
+// window that fits below r.
+func fixedBaseScalars(fx *Fixtures, w int) []mcl.Fr {
+	out := append([]mcl.Fr(nil), fx.Fr[:8]...)
+	var k mcl.Fr
+	for _, v := range []int64{0, 1, -1, 1<<uint(w) - 1} {
+		k.SetInt64(v)
+		out = append(out, k)
+	}
+	top := new(big.Int).Lsh(big.NewInt(1<<uint(w)-1), uint(w*(254/w-1)))
+	if err := k.SetString(top.String(), 10); err != nil {
+		panic(err)
+	}
+	return append(out, k)
+}
+
+func TestG1TableMatchesG1Mul(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(8, 1)
+	for _, w := range []int{1, 5, 8} {
+		table := NewG1Table(&fx.G1[0], w)
+		for i, k := range fixedBaseScalars(fx, w) {
+			var got, want mcl.G1
+			table.Mul(&got, &k)
+			mcl.G1Mul(&want, &fx.G1[0], &k)
+			if !got.IsEqual(&want) {
+				t.Errorf("w=%d: table product differs from G1Mul for scalar %d (%s)", w, i, k.GetString(16))
+			}
+		}
+	}
+}
+
+func TestG2TableMatchesG2Mul(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(8, 1)
+	for _, w := range []int{1, 5, 8} {
+		table := NewG2Table(&fx.G2[0], w)
+		for i, k := range fixedBaseScalars(fx, w) {
+			var got, want mcl.G2
+			table.Mul(&got, &k)
+			mcl.G2Mul(&want, &fx.G2[0], &k)
+			if !got.IsEqual(&want) {
+				t.Errorf("w=%d: table product differs from G2Mul for scalar %d (%s)", w, i, k.GetString(16))
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package mclbench
+
+import (
+	"math/big"
+
+	"github.com/alinush/go-mcl"
+)
+
+// frOrder returns the order r of Fr for the current curve.
+func frOrder() *big.Int {
+	r, _ := new(big.Int).SetString(mcl.GetCurveOrder(), 10)
+	return r
+}
+
+// FrPow sets out = x^e by left-to-right square-and-multiply over FrSqr and FrMul.
+func FrPow(out, x *mcl.Fr, e *big.Int) {
+	var acc mcl.Fr
+	acc.SetInt64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		mcl.FrSqr(&acc, &acc)
+		if e.Bit(i) == 1 {
+			mcl.FrMul(&acc, &acc, x)
+		}
+	}
+	*out = acc
+}
+
+// FrLegendre returns the Legendre symbol of x, by Euler's criterion
+// x^((r-1)/2) with half = (r-1)/2.
+func FrLegendre(x *mcl.Fr, half *big.Int) int {
+	if x.IsZero() {
+		return 0
+	}
+	var t mcl.Fr
+	FrPow(&t, x, half)
+	if t.IsOne() {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,78 @@
+package mclbench
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func TestFrPowSmallExponents(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(4, 1)
+	for _, x := range fx.Fr {
+		var want mcl.Fr
+		want.SetInt64(1)
+		for e := int64(0); e <= 17; e++ {
+			var got mcl.Fr
+			FrPow(&got, &x, big.NewInt(e))
+			if !got.IsEqual(&want) {
+				t.Errorf("FrPow(x, %d) differs from %d FrMuls", e, e)
+			}
+			mcl.FrMul(&want, &want, &x)
+		}
+	}
+}
+
+func TestFrPowMatchesModExp(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(8, 1)
+	rMinus1 := frOrder()
+	rMinus1.Sub(rMinus1, big.NewInt(1))
+	for j := range fx.Fr {
+		x, k := &fx.Fr[j], &fx.Fr[(j+1)%len(fx.Fr)]
+		var got mcl.Fr
+		FrPow(&got, x, k.ToBigInt())
+		if want := mcl.FrModExp_Slow(x, k); !got.IsEqual(want) {
+			t.Errorf("FrPow differs from FrModExp_Slow for fixture %d", j)
+		}
+		// Fermat: x^(r-1) = 1
+		if FrPow(&got, x, rMinus1); !got.IsOne() {
+			t.Errorf("x^(r-1) is not 1 for fixture %d", j)
+		}
+	}
+}
+
+func TestFrLegendre(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(32, 1)
+	half := frOrder()
+	half.Rsh(half, 1)
+
+	var zero mcl.Fr
+	if got := FrLegendre(&zero, half); got != 0 {
+		t.Errorf("FrLegendre(0) = %d, want 0", got)
+	}
+	seen := make(map[int]bool)
+	for j := range fx.Fr {
+		x := &fx.Fr[j]
+		var sq, root mcl.Fr
+		mcl.FrSqr(&sq, x)
+		if got := FrLegendre(&sq, half); got != 1 {
+			t.Errorf("FrLegendre(x^2) = %d for fixture %d, want 1", got, j)
+		}
+
+		want := -1
+		if mcl.FrSquareRoot(&root, x) {
+			want = 1
+		}
+		got := FrLegendre(x, half)
+		if got != want {
+			t.Errorf("FrLegendre = %d for fixture %d, but FrSquareRoot says %d", got, j, want)
+		}
+		seen[got] = true
+	}
+	if !seen[1] || !seen[-1] {
+		t.Errorf("the fixtures do not cover both residues and non-residues: %v", seen)
+	}
+}
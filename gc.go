@@ -0,0 +1,60 @@
+package mclbench
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+// Extra metrics recorded for every case: the GCs and GC pause time during
+// the final, measured round of testing.Benchmark.
+const (
+	gcCountUnit = "gcs"
+	gcPauseUnit = "gc-pause-us"
+)
+
+// withGCStats wraps the body of c to report the GCs it triggered. The
+// snapshots stop the world, so they are taken with the timer stopped.
+func withGCStats(c Case) Case {
+	bench := c.Bench
+	c.Bench = func(b *testing.B, fx *Fixtures, n int) {
+		var before, after runtime.MemStats
+		b.StopTimer()
+		runtime.ReadMemStats(&before)
+		b.StartTimer()
+		bench(b, fx, n)
+		b.StopTimer()
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC), gcCountUnit)
+		b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/1e3, gcPauseUnit)
+	}
+	return c
+}
+
+// NoGCHeadroom is how far the heap may grow in one round with the collector
+// disabled before the memory limit forces a collection anyway.
+var NoGCHeadroom int64 = 1 << 30
+
+// withoutGC wraps the body of c so that every round of testing.Benchmark,
+// including the b.N ramp-up, starts after an explicit collection and runs
+// with the collector disabled. Cases that allocate in their loop would
+// otherwise grow the heap without bound, so a memory limit of NoGCHeadroom
+// above the current use still forces collections, which withGCStats records.
+func withoutGC(c Case) Case {
+	bench := c.Bench
+	c.Bench = func(b *testing.B, fx *Fixtures, n int) {
+		var ms runtime.MemStats
+		b.StopTimer()
+		runtime.GC()
+		runtime.ReadMemStats(&ms)
+		oldPercent := debug.SetGCPercent(-1)
+		oldLimit := debug.SetMemoryLimit(int64(ms.Sys-ms.HeapReleased) + NoGCHeadroom)
+		defer func() {
+			debug.SetGCPercent(oldPercent)
+			debug.SetMemoryLimit(oldLimit)
+		}()
+		b.StartTimer()
+		bench(b, fx, n)
+	}
+	return c
+}
@@ -1,9 +1,14 @@
 module github.com/sshravan/go-mcl-benchmarks
 
-go 1.16
+go 1.19
 
 require (
 	github.com/alinush/go-mcl v0.0.0-20210224202455-eb6000c9b115
 	github.com/dustin/go-humanize v1.0.0
 	golang.org/x/text v0.3.5
 )
+
+require (
+	github.com/sirupsen/logrus v1.7.1 // indirect
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+)
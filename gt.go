@@ -0,0 +1,393 @@
+package mclbench
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/alinush/go-mcl"
+)
+
+// GT operations the binding does not expose, written over its Fp2 functions.
+// They read GT as mcl lays out Fp12: Fp6 a, b of Fp2 a, b, c each, over
+// Fp12 = Fp6[w]/(w^2 - v) and Fp6 = Fp2[v]/(v^3 - xi). The cases check them
+// against mcl before timing and are skipped if they disagree. They need
+// inputs in the cyclotomic subgroup, so the GT cases in this file read
+// fx.Unitary(); GTMul, GTPow and GTIsEqual in pairing.go still read fx.GT.
+
+const gtSkipReason = "GT is not laid out as mcl's Fp12"
+
+// gtCoeffs views x as its six Fp2 coefficients a.a, a.b, a.c, b.a, b.b, b.c.
+func gtCoeffs(x *mcl.GT) *[6]mcl.Fp2 {
+	return (*[6]mcl.Fp2)(unsafe.Pointer(x))
+}
+
+// gtLayoutOK reports whether GT has the size of six Fp2.
+func gtLayoutOK() bool {
+	return unsafe.Sizeof(mcl.GT{}) == 6*unsafe.Sizeof(mcl.Fp2{})
+}
+
+// mulByXi sets out = x * (xiA + i).
+func mulByXi(out, x *mcl.Fp2, xiA *mcl.Fp) {
+	var t0, t1 mcl.Fp
+	mcl.FpMul(&t0, &x.D[0], xiA)
+	mcl.FpSub(&t0, &t0, &x.D[1])
+	mcl.FpMul(&t1, &x.D[1], xiA)
+	mcl.FpAdd(&t1, &t1, &x.D[0])
+	out.D[0], out.D[1] = t0, t1
+}
+
+// fp4Sqr sets (r0, r1) = (a + b*s)^2 in Fp4 = Fp2[s]/(s^2 - xi).
+func fp4Sqr(r0, r1, a, b *mcl.Fp2, xiA *mcl.Fp) {
+	var t, u, v mcl.Fp2
+	mcl.Fp2Mul(&t, a, b)
+	mulByXi(&u, b, xiA)
+	mcl.Fp2Add(&u, &u, a)
+	mcl.Fp2Add(&v, a, b)
+	mcl.Fp2Mul(&u, &u, &v)
+	mcl.Fp2Sub(&u, &u, &t)
+	mulByXi(&v, &t, xiA)
+	mcl.Fp2Sub(r0, &u, &v)
+	mcl.Fp2Add(r1, &t, &t)
+}
+
+// GTCyclotomicSqr sets out = x^2 for x in the cyclotomic subgroup (Granger
+// and Scott), with xi = xiA + i the non-residue of the curve's tower.
+func GTCyclotomicSqr(out, x *mcl.GT, xiA *mcl.Fp) {
+	z := gtCoeffs(x)
+	r0, r4, r3, r2, r1, r5 := z[0], z[1], z[2], z[3], z[4], z[5]
+	var t0, t1, t2, t3, t4, t5, tmp mcl.Fp2
+	fp4Sqr(&t0, &t1, &r0, &r1, xiA)
+	fp4Sqr(&t2, &t3, &r2, &r3, xiA)
+	fp4Sqr(&t4, &t5, &r4, &r5, xiA)
+
+	// 3t - 2z or 3t + 2z per coefficient
+	triple := func(out, t, z *mcl.Fp2, minus bool) {
+		var u mcl.Fp2
+		if minus {
+			mcl.Fp2Sub(&u, t, z)
+		} else {
+			mcl.Fp2Add(&u, t, z)
+		}
+		mcl.Fp2Add(&u, &u, &u)
+		mcl.Fp2Add(out, &u, t)
+	}
+	o := gtCoeffs(out)
+	triple(&o[0], &t0, &r0, true)
+	triple(&o[4], &t1, &r1, false)
+	mulByXi(&tmp, &t5, xiA)
+	triple(&o[3], &tmp, &r2, false)
+	triple(&o[2], &t4, &r3, true)
+	triple(&o[1], &t2, &r4, true)
+	triple(&o[5], &t3, &r5, false)
+}
+
+// GTCompressed is Karabina's compressed form of a cyclotomic-subgroup element:
+// four of its six Fp2 coefficients.
+type GTCompressed [4]mcl.Fp2
+
+func GTCompress(out *GTCompressed, x *mcl.GT) {
+	z := gtCoeffs(x)
+	out[0], out[1], out[2], out[3] = z[1], z[2], z[3], z[5]
+}
+
+// GTDecompress recovers the two dropped coefficients, which costs one Fp2
+// division.
+func GTDecompress(out *mcl.GT, c *GTCompressed, xiA *mcl.Fp) {
+	g1, g2, g3, g5 := &c[0], &c[1], &c[2], &c[3]
+	var t0, t1, t2, g4, g0 mcl.Fp2
+	if g3.IsZero() {
+		// g4 = 2 g1 g5 / g2
+		mcl.Fp2Mul(&t0, g1, g5)
+		mcl.Fp2Add(&t0, &t0, &t0)
+		t1 = *g2
+		if t1.IsZero() {
+			out.SetInt64(1)
+			return
+		}
+	} else {
+		// g4 = (xi g5^2 + 3 g1^2 - 2 g2) / 4 g3
+		mcl.Fp2Sqr(&t0, g1)
+		mcl.Fp2Sub(&t1, &t0, g2)
+		mcl.Fp2Add(&t1, &t1, &t1)
+		mcl.Fp2Add(&t1, &t1, &t0)
+		mcl.Fp2Sqr(&t2, g5)
+		mulByXi(&t0, &t2, xiA)
+		mcl.Fp2Add(&t0, &t0, &t1)
+		mcl.Fp2Add(&t1, g3, g3)
+		mcl.Fp2Add(&t1, &t1, &t1)
+	}
+	mcl.Fp2Div(&g4, &t0, &t1)
+
+	// g0 = xi (2 g4^2 + g3 g5 - 3 g1 g2) + 1
+	mcl.Fp2Mul(&t1, g2, g1)
+	mcl.Fp2Sqr(&t2, &g4)
+	mcl.Fp2Sub(&t2, &t2, &t1)
+	mcl.Fp2Add(&t2, &t2, &t2)
+	mcl.Fp2Sub(&t2, &t2, &t1)
+	mcl.Fp2Mul(&t1, g3, g5)
+	mcl.Fp2Add(&t2, &t2, &t1)
+	mulByXi(&g0, &t2, xiA)
+	var one mcl.Fp
+	one.SetInt64(1)
+	mcl.FpAdd(&g0.D[0], &g0.D[0], &one)
+
+	z := gtCoeffs(out)
+	z[0], z[1], z[2], z[3], z[4], z[5] = g0, *g1, *g2, *g3, g4, *g5
+}
+
+// towerXi finds the xi of the current curve (1 + i for BLS12-381 and BN254,
+// 9 + i for bn254_snark) by checking GTCyclotomicSqr against GTMul on x.
+func towerXi(x *mcl.GT) (*mcl.Fp, bool) {
+	if !gtLayoutOK() {
+		return nil, false
+	}
+	var want, got mcl.GT
+	mcl.GTMul(&want, x, x)
+	for _, a := range []int64{1, 9} {
+		var xiA mcl.Fp
+		xiA.SetInt64(a)
+		GTCyclotomicSqr(&got, x, &xiA)
+		if got.IsEqual(&want) {
+			return &xiA, true
+		}
+	}
+	return nil, false
+}
+
+// gtLayoutCases are the cases that read GT as mcl's Fp12 and skip with
+// gtSkipReason when it is laid out differently.
+var gtLayoutCases = map[string]bool{"GTCyclotomicSqr": true, "GTDecompress": true}
+
+// gtLayoutWarning checks the GT layout once for a run of cases, since the
+// skip reason of a case does not reach the report. It returns "" when no such
+// case is selected or the layout is mcl's.
+func gtLayoutWarning(cases []Case, fx *Fixtures) string {
+	var names []string
+	for _, c := range cases {
+		if gtLayoutCases[c.Name] {
+			names = append(names, c.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	gt := fx.Unitary()
+	xiA, ok := towerXi(&gt[0])
+	if ok {
+		var compressed GTCompressed
+		var result mcl.GT
+		GTCompress(&compressed, &gt[0])
+		GTDecompress(&result, &compressed, xiA)
+		ok = result.IsEqual(&gt[0])
+	}
+	if ok {
+		return ""
+	}
+	return fmt.Sprintf("%s skipped: %s", strings.Join(names, ", "), gtSkipReason)
+}
+
+// PippengerGT is PippengerG1 in GT, with unitary inversion for negative digits.
+func PippengerGT(out *mcl.GT, bases []mcl.GT, scalars []mcl.Fr, c int) {
+	if len(bases) != len(scalars) {
+		panic("bases and scalars must have the same length")
+	}
+	n := numWindows(c) + 1
+	digits := make([][]int, len(scalars))
+	for j := range scalars {
+		digits[j] = signedDigits(scalars[j].Serialize(), c, n)
+	}
+
+	buckets := make([]mcl.GT, 1<<uint(c-1))
+	var inv, running, sum mcl.GT
+	out.SetInt64(1)
+	for w := n - 1; w >= 0; w-- {
+		for b := 0; b < c; b++ {
+			mcl.GTMul(out, out, out)
+		}
+		for b := range buckets {
+			buckets[b].SetInt64(1)
+		}
+		for j := range bases {
+			d := digits[j][w]
+			if d > 0 {
+				mcl.GTMul(&buckets[d-1], &buckets[d-1], &bases[j])
+			} else if d < 0 {
+				mcl.GTInv(&inv, &bases[j])
+				mcl.GTMul(&buckets[-d-1], &buckets[-d-1], &inv)
+			}
+		}
+		running.SetInt64(1)
+		sum.SetInt64(1)
+		for b := len(buckets) - 1; b >= 0; b-- {
+			mcl.GTMul(&running, &running, &buckets[b])
+			mcl.GTMul(&sum, &sum, &running)
+		}
+		mcl.GTMul(out, out, &sum)
+	}
+}
+
+// checkPippengerGT compares PippengerGT with a product of GTPow calls.
+func checkPippengerGT(bases []mcl.GT, scalars []mcl.Fr, c int) error {
+	var got, want, tmp mcl.GT
+	PippengerGT(&got, bases, scalars, c)
+	want.SetInt64(1)
+	for j := range bases {
+		mcl.GTPow(&tmp, &bases[j], &scalars[j])
+		mcl.GTMul(&want, &want, &tmp)
+	}
+	if !got.IsEqual(&want) {
+		return fmt.Errorf("PippengerGT with c=%d over %d bases differs from the GTPow product", c, len(bases))
+	}
+	return nil
+}
+
+// pippengerWindow is a window size close to the best for n bases.
+func pippengerWindow(n int) int {
+	c := bits.Len(uint(n)) - 2
+	if c < 2 {
+		return 2
+	}
+	if c > 12 {
+		return 12
+	}
+	return c
+}
+
+func registerGT() {
+
+	// The binding has no GT squaring, so this is the general multiplication the
+	// cyclotomic squaring replaces.
+	Register(Case{Name: "GTMulSelf", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTMul(&result, &gt[j], &gt[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "GTCyclotomicSqr", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		xiA, ok := towerXi(&gt[0])
+		if !ok {
+			t.Skip(gtSkipReason)
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				GTCyclotomicSqr(&result, &gt[j], xiA)
+			}
+		}
+	}})
+	// =============================================
+	// mcl's GTInv is the conjugate, the inverse of a unitary element such as a pairing output.
+	Register(Case{Name: "GTInvUnitary", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTInv(&result, &gt[j])
+			}
+		}
+	}})
+	// =============================================
+	// A true Fp12 inversion; the binding only exposes it through GTDiv.
+	Register(Case{Name: "GTInvGeneric", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result, one mcl.GT
+		one.SetInt64(1)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTDiv(&result, &one, &gt[j])
+			}
+		}
+	}})
+	// =============================================
+	// 63-bit exponents, as in small-exponent batch verification.
+	Register(Case{Name: "GTPowSmall", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		rng := rand.New(rand.NewSource(int64(n)))
+		small := make([]mcl.Fr, n)
+		for j := range small {
+			small[j].SetInt64(rng.Int63())
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTPow(&result, &gt[j], &small[j])
+			}
+		}
+	}})
+	// =============================================
+	// The binding has no GT multi-exponentiation; this is the GTPow loop it replaces.
+	Register(Case{Name: "GTPowLoop", Group: "GT", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result, tmp mcl.GT
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			result.SetInt64(1)
+			for j := 0; j < n; j++ {
+				mcl.GTPow(&tmp, &gt[j], &fx.Fr[j])
+				mcl.GTMul(&result, &result, &tmp)
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "GTMultiExp", Group: "GT", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		c := pippengerWindow(n)
+		if err := checkPippengerGT(gt[:n], fx.Fr[:n], c); err != nil {
+			t.Fatal(err)
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			PippengerGT(&result, gt[:n], fx.Fr[:n], c)
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "GTCompress", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result GTCompressed
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				GTCompress(&result, &gt[j])
+			}
+		}
+		t.ReportMetric(float64(8*mcl.GetFpByteSize()), "bytes")
+	}})
+	// =============================================
+	Register(Case{Name: "GTDecompress", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		gt := fx.Unitary()
+		var result mcl.GT
+		xiA, ok := towerXi(&gt[0])
+		compressed := make([]GTCompressed, n)
+		for j := range compressed {
+			GTCompress(&compressed[j], &gt[j])
+		}
+		if ok {
+			GTDecompress(&result, &compressed[0], xiA)
+			ok = result.IsEqual(&gt[0])
+		}
+		if !ok {
+			t.Skip(gtSkipReason)
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				GTDecompress(&result, &compressed[j], xiA)
+			}
+		}
+	}})
+}
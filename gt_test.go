@@ -0,0 +1,18 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func TestPippengerGTMatchesGTPow(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	gt := fx.Unitary()
+	for _, n := range []int{1, 2, 5, 64} {
+		if err := checkPippengerGT(gt[:n], fx.Fr[:n], pippengerWindow(n)); err != nil {
+			t.Error(err)
+		}
+	}
+}
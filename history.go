@@ -0,0 +1,259 @@
+package mclbench
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The history is an append-only JSON-lines file with one HistoryEntry per
+// run, so results from earlier runs survive the results file being overwritten.
+
+const mclModule = "github.com/alinush/go-mcl"
+
+type HistoryEntry struct {
+	Fingerprint string             `json:"fingerprint"`
+	Host        string             `json:"host"`
+	Curve       string             `json:"curve"`
+	MclVersion  string             `json:"mcl_version"`
+	Time        time.Time          `json:"time"`
+	Values      map[string]float64 `json:"values"`
+	Env         *Environment       `json:"env,omitempty"`
+}
+
+// MclVersion is the go-mcl module version this binary was built with.
+func MclVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, m := range info.Deps {
+		if m.Path != mclModule {
+			continue
+		}
+		if m.Replace != nil {
+			return m.Replace.Path + "@" + m.Replace.Version
+		}
+		return m.Version
+	}
+	return "unknown"
+}
+
+func cpuModel() string {
+	fields, _, _ := cpuinfo()
+	return fields["model name"]
+}
+
+// Fingerprint identifies the machine: host, platform, CPU count and model.
+func Fingerprint() string {
+	host, _ := os.Hostname()
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%s/%s|%d|%s", host, runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), cpuModel())))
+	return fmt.Sprintf("%x", h[:6])
+}
+
+func NewHistoryEntry(run *Run) HistoryEntry {
+	host, _ := os.Hostname()
+	return HistoryEntry{
+		Fingerprint: Fingerprint(),
+		Host:        host,
+		Curve:       run.Curve,
+		MclVersion:  MclVersion(),
+		Time:        run.Started,
+		Values:      run.Values(),
+		Env:         run.Env,
+	}
+}
+
+func AppendHistory(path string, e HistoryEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory reads every entry of path in file order.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []HistoryEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1<<20), 64<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(strings.TrimSpace(sc.Text())) == 0 {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// HistoryReporter appends the run to the history file at Path.
+type HistoryReporter struct {
+	Path string
+}
+
+func (h *HistoryReporter) Start(run *Run) {}
+
+func (h *HistoryReporter) Result(c Case, r Result) {}
+
+func (h *HistoryReporter) Finish(run *Run) error {
+	return AppendHistory(h.Path, NewHistoryEntry(run))
+}
+
+type TrendPoint struct {
+	Time       time.Time `json:"time"`
+	MclVersion string    `json:"mcl_version"`
+	Value      float64   `json:"value"`
+}
+
+// A Step is a point that moved more than the threshold away from the median
+// of the points since the previous step.
+type Step struct {
+	Index  int     `json:"index"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"` // relative, After/Before - 1
+}
+
+type Trend struct {
+	Case   string       `json:"case"`
+	Points []TrendPoint `json:"points"`
+	Steps  []Step       `json:"steps"`
+}
+
+// Change is the relative change from the first to the last point.
+func (t Trend) Change() float64 {
+	if len(t.Points) < 2 || t.Points[0].Value == 0 {
+		return 0
+	}
+	return t.Points[len(t.Points)-1].Value/t.Points[0].Value - 1
+}
+
+func median(xs []float64) float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
+
+// stepWindow is the number of earlier points a new point is compared against.
+const stepWindow = 5
+
+func findSteps(points []TrendPoint, threshold float64) []Step {
+	var steps []Step
+	since := 0
+	for i := 1; i < len(points); i++ {
+		lo := i - stepWindow
+		if lo < since {
+			lo = since
+		}
+		var prev []float64
+		for _, p := range points[lo:i] {
+			prev = append(prev, p.Value)
+		}
+		before := median(prev)
+		if before == 0 {
+			continue
+		}
+		change := points[i].Value/before - 1
+		if math.Abs(change) > threshold {
+			steps = append(steps, Step{Index: i, Before: before, After: points[i].Value, Change: change})
+			since = i
+		}
+	}
+	return steps
+}
+
+// HistoryFilter selects the entries and cases of a trend report.
+// Empty fields match everything.
+type HistoryFilter struct {
+	Fingerprint string
+	Curve       string
+	Cases       *regexp.Regexp
+}
+
+func (f HistoryFilter) match(e HistoryEntry) bool {
+	return (f.Fingerprint == "" || e.Fingerprint == f.Fingerprint) &&
+		(f.Curve == "" || e.Curve == f.Curve)
+}
+
+// Trends builds the time series of every matching case, oldest first, and
+// marks relative changes larger than threshold.
+func Trends(entries []HistoryEntry, filter HistoryFilter, threshold float64) []Trend {
+	var selected []HistoryEntry
+	for _, e := range entries {
+		if filter.match(e) {
+			selected = append(selected, e)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Time.Before(selected[j].Time) })
+
+	series := make(map[string][]TrendPoint)
+	for _, e := range selected {
+		for k, v := range e.Values {
+			if filter.Cases != nil && !filter.Cases.MatchString(k) {
+				continue
+			}
+			series[k] = append(series[k], TrendPoint{Time: e.Time, MclVersion: e.MclVersion, Value: v})
+		}
+	}
+
+	names := make([]string, 0, len(series))
+	for k := range series {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	trends := make([]Trend, 0, len(names))
+	for _, k := range names {
+		trends = append(trends, Trend{Case: k, Points: series[k], Steps: findSteps(series[k], threshold)})
+	}
+	return trends
+}
+
+// Sparkline draws values as a row of block characters scaled to their range.
+func Sparkline(values []float64) string {
+	const bars = "▁▂▃▄▅▆▇█"
+	runes := []rune(bars)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(runes)-1))
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
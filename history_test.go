@@ -0,0 +1,33 @@
+package mclbench
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindSteps(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []Step
+	}{
+		{"flat", []float64{10, 10, 10, 10}, nil},
+		{"below threshold", []float64{8, 9.5}, nil},
+		{"step up", []float64{8, 8, 8, 10, 10, 10}, []Step{{3, 8, 10, 0.25}}},
+		{"step down", []float64{10, 10, 5, 5}, []Step{{2, 10, 5, -0.5}}},
+		// 10 is compared with the median 8 of the earlier points, not their mean 8.375.
+		{"median", []float64{8, 8, 9.5, 8, 10}, []Step{{4, 8, 10, 0.25}}},
+		// Only the last five points count: 10.5 is compared with 9.5, not 8.
+		{"window", []float64{8, 8, 8, 8, 8, 8, 9.5, 9.5, 9.5, 9.5, 9.5, 10.5}, nil},
+		{"zero median", []float64{0, 0, 10}, nil},
+	}
+	for _, tt := range tests {
+		points := make([]TrendPoint, len(tt.values))
+		for i, v := range tt.values {
+			points[i].Value = v
+		}
+		if got := findSteps(points, 0.2); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: findSteps = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
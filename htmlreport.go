@@ -0,0 +1,525 @@
+package mclbench
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A static HTML report with inline SVG charts, built from one or more
+// results files so it can be shared without any plotting tools.
+
+// A ResultSet is one results file under the label shown in the legends,
+// typically the curve or machine it was recorded on.
+type ResultSet struct {
+	Label   string
+	Values  map[string]float64
+	Env     *Environment    // nil if unknown
+	Latency []LatencyResult // per-call histograms, if recorded
+}
+
+var palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+func color(i int) string {
+	return palette[i%len(palette)]
+}
+
+// headlineCases are compared across result sets.
+var headlineCases = []string{"FrMul", "FrInv", "G1Add", "G1Mul", "G2Add", "G2Mul", "GTMul", "GTPow", "MillerLoop", "FinalExp", "Pairing"}
+
+// scalingCharts are the log-log plots of total time against vector size.
+var scalingCharts = []struct {
+	Title string
+	Cases []string
+}{
+	{"MSM scaling", []string{"G1MulVec", "G2MulVec", "G1MulLoop", "PippengerG1"}},
+	{"Multi-pairing scaling", []string{"MultiPairing", "MillerLoopVec", "PrecomputedMillerLoopProduct"}},
+}
+
+// parallelKey matches results recorded with a thread count, Name_p<threads>.
+var parallelKey = regexp.MustCompile(`^(.+)_p(\d+)$`)
+
+// isCaseKey reports whether key is the results key of a registered case, as
+// opposed to a derived metric or a reducer output such as PippengerG1<n>.
+func isCaseKey(sized []sizedCase, key string) bool {
+	if _, _, ok := splitSizedKey(sized, key); ok {
+		return true
+	}
+	for _, c := range registry {
+		if !c.Vector && c.Name == key {
+			return true
+		}
+	}
+	return false
+}
+
+func fmtNs(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.3g s", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.3g ms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.3g us", ns/1e3)
+	}
+	return fmt.Sprintf("%.3g ns", ns)
+}
+
+func legend(sb *strings.Builder, x, y float64, labels []string) {
+	for i, l := range labels {
+		fmt.Fprintf(sb, `<rect x="%.1f" y="%.1f" width="10" height="10" fill="%s"/>`, x, y+float64(i)*16, color(i))
+		fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" font-size="12">%s</text>`, x+14, y+float64(i)*16+9, html.EscapeString(l))
+	}
+}
+
+// barChart draws horizontal bars, one row per category and one bar per series.
+func barChart(title string, categories []string, series []string, value func(cat string, s int) (float64, bool), format func(float64) string) string {
+	const width, labelW, legendW, barH, gap = 900.0, 170.0, 160.0, 12.0, 8.0
+	plotW := width - labelW - legendW - 80
+	rowH := float64(len(series))*barH + gap
+	height := 30 + float64(len(categories))*rowH + 10
+
+	max := 0.0
+	for _, c := range categories {
+		for s := range series {
+			if v, ok := value(c, s); ok {
+				max = math.Max(max, v)
+			}
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif">`, width, height)
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-size="15" font-weight="bold">%s</text>`, html.EscapeString(title))
+	for i, c := range categories {
+		y := 30 + float64(i)*rowH
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="end">%s</text>`, labelW-6, y+rowH/2, html.EscapeString(c))
+		for s := range series {
+			v, ok := value(c, s)
+			if !ok {
+				continue
+			}
+			w := v / max * plotW
+			by := y + float64(s)*barH
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %s: %s</title></rect>`,
+				labelW, by, w, barH-1, color(s), html.EscapeString(series[s]), html.EscapeString(c), format(v))
+			fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="10">%s</text>`, labelW+w+4, by+barH-3, format(v))
+		}
+	}
+	fmt.Fprintf(&sb, `<line x1="%.1f" y1="28" x2="%.1f" y2="%.1f" stroke="#888"/>`, labelW, labelW, height-10)
+	legend(&sb, width-legendW, 30, series)
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+type line struct {
+	Label  string
+	Points [][2]float64
+}
+
+type mark struct {
+	Label string
+	X     float64
+}
+
+// logLogChart plots lines on log10 axes with ticks at each decade and a
+// dashed vertical line at each mark.
+func logLogChart(title, xLabel, yLabel string, lines []line, marks []mark, format func(float64) string) string {
+	const width, height, left, right, top, bottom = 900.0, 420.0, 70.0, 260.0, 30.0, 40.0
+	minX, maxX, minY, maxY := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, l := range lines {
+		for _, p := range l.Points {
+			minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+			minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+		}
+	}
+	lx0, lx1 := math.Floor(math.Log10(minX)), math.Ceil(math.Log10(maxX))
+	ly0, ly1 := math.Floor(math.Log10(minY)), math.Ceil(math.Log10(maxY))
+	if lx1 == lx0 {
+		lx1++
+	}
+	if ly1 == ly0 {
+		ly1++
+	}
+	px := func(x float64) float64 { return left + (math.Log10(x)-lx0)/(lx1-lx0)*(width-left-right) }
+	py := func(y float64) float64 { return height - bottom - (math.Log10(y)-ly0)/(ly1-ly0)*(height-top-bottom) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif">`, width, height)
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-size="15" font-weight="bold">%s</text>`, html.EscapeString(title))
+	for e := lx0; e <= lx1; e++ {
+		x := px(math.Pow(10, e))
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`, x, top, x, height-bottom)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="middle">1e%d</text>`, x, height-bottom+14, int(e))
+	}
+	for e := ly0; e <= ly1; e++ {
+		y := py(math.Pow(10, e))
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`, left, y, width-right, y)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="end">%s</text>`, left-4, y+4, format(math.Pow(10, e)))
+	}
+	fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="middle">%s</text>`, (left+width-right)/2, height-6, html.EscapeString(xLabel))
+	fmt.Fprintf(&sb, `<text x="12" y="%.1f" font-size="12" text-anchor="middle" transform="rotate(-90 12 %.1f)">%s</text>`, height/2, height/2, html.EscapeString(yLabel))
+
+	for _, m := range marks {
+		if m.X < math.Pow(10, lx0) || m.X > math.Pow(10, lx1) {
+			continue
+		}
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999" stroke-dasharray="4 3"/>`, px(m.X), top, px(m.X), height-bottom)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="11">%s</text>`, px(m.X)+3, top+10, html.EscapeString(m.Label))
+	}
+
+	labels := make([]string, len(lines))
+	for i, l := range lines {
+		labels[i] = l.Label
+		var pts []string
+		for _, p := range l.Points {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", px(p[0]), py(p[1])))
+			fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s n=%g: %s</title></circle>`,
+				px(p[0]), py(p[1]), color(i), html.EscapeString(l.Label), p[0], format(p[1]))
+		}
+		fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(pts, " "), color(i))
+	}
+	legend(&sb, width-right+20, top, labels)
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+func setLabels(sets []ResultSet) []string {
+	labels := make([]string, len(sets))
+	for i, s := range sets {
+		labels[i] = s.Label
+	}
+	return labels
+}
+
+// groupCharts draws one bar chart per group of the per-element cases.
+func groupCharts(sets []ResultSet) []template.HTML {
+	var out []template.HTML
+	labels := setLabels(sets)
+	for _, g := range Groups() {
+		var cats []string
+		for _, c := range Cases() {
+			if c.Group != g || c.Vector {
+				continue
+			}
+			for _, s := range sets {
+				if _, ok := s.Values[c.Key(0)]; ok {
+					cats = append(cats, c.Key(0))
+					break
+				}
+			}
+		}
+		if len(cats) == 0 {
+			continue
+		}
+		chart := barChart(g+" (time per element)", cats, labels, func(cat string, i int) (float64, bool) {
+			v, ok := sets[i].Values[cat]
+			return v, ok
+		}, fmtNs)
+		out = append(out, template.HTML(chart))
+	}
+	return out
+}
+
+func scalingLines(sets []ResultSet, cases []string) []line {
+	var lines []line
+	for _, s := range sets {
+		for _, c := range cases {
+			var l line
+			l.Label = c
+			if len(sets) > 1 {
+				l.Label = s.Label + " " + c
+			}
+			for _, n := range sizesOf(c+"{n}", s.Values) {
+				l.Points = append(l.Points, [2]float64{float64(n), s.Values[c+strconv.Itoa(n)]})
+			}
+			if len(l.Points) > 1 {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
+
+// comparisonChart shows the headline cases of every set relative to the first.
+func comparisonChart(sets []ResultSet) template.HTML {
+	var cats []string
+	for _, c := range headlineCases {
+		if _, ok := sets[0].Values[c]; ok {
+			cats = append(cats, c)
+		}
+	}
+	chart := barChart("Relative to "+sets[0].Label+" (lower is faster)", cats, setLabels(sets), func(cat string, i int) (float64, bool) {
+		v, ok := sets[i].Values[cat]
+		base := sets[0].Values[cat]
+		if !ok || base == 0 {
+			return 0, false
+		}
+		return v / base, true
+	}, func(r float64) string { return fmt.Sprintf("%.2fx", r) })
+	return template.HTML(chart)
+}
+
+// parallelChart plots the speedup of Name_p<threads> over Name_p1.
+func parallelChart(sets []ResultSet) (template.HTML, bool) {
+	var lines []line
+	sized := sizedCases()
+	for _, s := range sets {
+		byCase := make(map[string][][2]float64)
+		for k, v := range s.Values {
+			if m := parallelKey.FindStringSubmatch(k); m != nil && isCaseKey(sized, m[1]) {
+				p, _ := strconv.Atoi(m[2])
+				byCase[m[1]] = append(byCase[m[1]], [2]float64{float64(p), v})
+			}
+		}
+		names := make([]string, 0, len(byCase))
+		for k := range byCase {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pts := byCase[name]
+			sort.Slice(pts, func(i, j int) bool { return pts[i][0] < pts[j][0] })
+			if len(pts) < 2 || pts[0][0] != 1 {
+				continue
+			}
+			l := line{Label: name}
+			if len(sets) > 1 {
+				l.Label = s.Label + " " + name
+			}
+			for _, p := range pts {
+				l.Points = append(l.Points, [2]float64{p[0], pts[0][1] / p[1]})
+			}
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) == 0 {
+		return "", false
+	}
+	return template.HTML(logLogChart("Parallel scaling", "threads", "speedup over 1 thread", lines, nil, func(r float64) string { return fmt.Sprintf("%.3gx", r) })), true
+}
+
+// envTable has one row per kind of environment line and one column per set, or is
+// empty if no set has an environment.
+func envTable(sets []ResultSet) [][]string {
+	var rows [][]string
+	index := make(map[string]int)
+	for i, s := range sets {
+		if s.Env == nil {
+			continue
+		}
+		for _, l := range s.Env.Lines() {
+			k := strings.Index(l, ": ")
+			j, ok := index[l[:k]]
+			if !ok {
+				j = len(rows)
+				index[l[:k]] = j
+				rows = append(rows, append([]string{l[:k]}, make([]string, len(sets))...))
+			}
+			rows[j][i+1] = l[k+2:]
+		}
+	}
+	if rows != nil {
+		rows = append([][]string{append([]string{""}, setLabels(sets)...)}, rows...)
+	}
+	return rows
+}
+
+// derivedTable lists the derived metrics of every set, recomputed from its
+// values, one row per metric.
+func derivedTable(sets []ResultSet) [][]string {
+	derived := make([]map[string]float64, len(sets))
+	var names []string
+	seen := make(map[string]bool)
+	for i, s := range sets {
+		derived[i] = ComputeDerived(s.Values)
+		for k := range derived[i] {
+			if !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	rows := [][]string{append([]string{""}, setLabels(sets)...)}
+	for _, k := range names {
+		row := []string{k}
+		for i := range sets {
+			cell := ""
+			if v, ok := derived[i][k]; ok {
+				cell = fmt.Sprintf("%.3f", v)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// workingSetChart plots the per-element cost of the working-set sweep against
+// the working-set size, with the caches of the first set that has them marked.
+func workingSetChart(sets []ResultSet) (template.HTML, bool) {
+	var lines []line
+	var marks []mark
+	for _, s := range sets {
+		byOp := make(map[string][][2]float64)
+		for k, v := range s.Values {
+			if m := workingSetKey.FindStringSubmatch(k); m != nil {
+				b, _ := strconv.Atoi(m[2])
+				name := m[1]
+				if m[3] != "" {
+					name += " random"
+				}
+				byOp[name] = append(byOp[name], [2]float64{float64(b), v})
+			}
+		}
+		names := make([]string, 0, len(byOp))
+		for k := range byOp {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pts := byOp[name]
+			sort.Slice(pts, func(i, j int) bool { return pts[i][0] < pts[j][0] })
+			l := line{Label: name, Points: pts}
+			if len(sets) > 1 {
+				l.Label = s.Label + " " + name
+			}
+			lines = append(lines, l)
+		}
+		if marks == nil && s.Env != nil && len(byOp) > 0 {
+			for _, c := range s.Env.Caches {
+				if c.Type != "Instruction" {
+					marks = append(marks, mark{fmt.Sprintf("L%d", c.Level), float64(c.Bytes)})
+				}
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return "", false
+	}
+	return template.HTML(logLogChart("Working set", "working set (bytes)", "time per element", lines, marks, fmtNs)), true
+}
+
+// histogramChart draws the buckets of one latency histogram as columns on a
+// log10 latency axis, with the quantiles marked.
+func histogramChart(title string, r LatencyResult) template.HTML {
+	const width, height, left, right, top, bottom = 900.0, 260.0, 60.0, 20.0, 30.0, 40.0
+	if len(r.Buckets) == 0 {
+		return ""
+	}
+	lx0 := math.Floor(math.Log10(math.Max(1, float64(r.Buckets[0].Lo))))
+	lx1 := math.Ceil(math.Log10(float64(r.Buckets[len(r.Buckets)-1].Hi)))
+	if lx1 == lx0 {
+		lx1++
+	}
+	var maxCount int64
+	for _, b := range r.Buckets {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	px := func(x float64) float64 {
+		return left + (math.Log10(math.Max(1, x))-lx0)/(lx1-lx0)*(width-left-right)
+	}
+	py := func(c int64) float64 { return height - bottom - float64(c)/float64(maxCount)*(height-top-bottom) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif">`, width, height)
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-size="15" font-weight="bold">%s</text>`, html.EscapeString(title))
+	for e := lx0; e <= lx1; e++ {
+		x := px(math.Pow(10, e))
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`, x, top, x, height-bottom)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="middle">%s</text>`, x, height-bottom+14, fmtNs(math.Pow(10, e)))
+	}
+	for _, b := range r.Buckets {
+		x0, x1 := px(float64(b.Lo)), px(float64(b.Hi))
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.2f" height="%.1f" fill="%s"><title>%s - %s: %d</title></rect>`,
+			x0, py(b.Count), math.Max(x1-x0, 0.5), py(0)-py(b.Count), color(0), fmtNs(float64(b.Lo)), fmtNs(float64(b.Hi)), b.Count)
+	}
+	for i, q := range latencyQuantiles {
+		x := px(r.Quantiles[q.Name])
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="4 3"/>`, x, top, x, height-bottom, color(i+1))
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="11" fill="%s">%s %s</text>`, x+3, top+10+float64(i)*12, color(i+1), q.Name, fmtNs(r.Quantiles[q.Name]))
+	}
+	fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="middle">latency (%d calls, max %s)</text>`, (left+width-right)/2, height-6, r.Count, fmtNs(r.Max))
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String())
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title>
+<style>body{font-family:sans-serif;margin:2em;max-width:960px} h2{margin-top:2em;border-bottom:1px solid #ccc} svg{display:block;margin:1em 0} .warn{background:#fde;padding:4px 8px} td{padding:2px 8px;border-bottom:1px solid #eee;font-size:13px}</style>
+</head><body>
+<h1>{{.Title}}</h1>
+<p>Result sets: {{range $i, $l := .Labels}}{{if $i}}, {{end}}{{$l}}{{end}}</p>
+{{range .Warnings}}<p class="warn">{{.}}</p>{{end}}
+{{if .Env}}<h2>Environment</h2><table>{{range .Env}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>{{end}}</table>{{end}}
+{{if .Comparison}}<h2>Comparison</h2>{{.Comparison}}{{end}}
+{{if .Derived}}<h2>Derived metrics</h2><table>{{range .Derived}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>{{end}}</table>{{end}}
+<h2>Groups</h2>{{range .Groups}}{{.}}{{end}}
+{{if .Scaling}}<h2>Scaling</h2>{{range .Scaling}}{{.}}{{end}}{{end}}
+{{if .Parallel}}<h2>Parallel scaling</h2>{{.Parallel}}{{end}}
+{{if .WorkingSet}}<h2>Working set</h2>{{.WorkingSet}}{{end}}
+{{if .Latency}}<h2>Latency</h2>{{range .Latency}}{{.}}{{end}}{{end}}
+</body></html>
+`))
+
+// WriteHTMLReport renders sets as a single self-contained HTML page.
+func WriteHTMLReport(w io.Writer, title string, sets []ResultSet) error {
+	if len(sets) == 0 {
+		return fmt.Errorf("no result sets")
+	}
+	data := struct {
+		Title      string
+		Labels     []string
+		Comparison template.HTML
+		Groups     []template.HTML
+		Scaling    []template.HTML
+		Parallel   template.HTML
+		WorkingSet template.HTML
+		Latency    []template.HTML
+		Derived    [][]string
+		Env        [][]string
+		Warnings   []string
+	}{Title: title, Labels: setLabels(sets), Groups: groupCharts(sets), Derived: derivedTable(sets), Env: envTable(sets)}
+	for _, s := range sets {
+		if s.Env != nil {
+			for _, w := range s.Env.Warnings {
+				data.Warnings = append(data.Warnings, s.Label+": "+w)
+			}
+		}
+	}
+	if len(sets) > 1 {
+		data.Comparison = comparisonChart(sets)
+	}
+	for _, sc := range scalingCharts {
+		if lines := scalingLines(sets, sc.Cases); len(lines) > 0 {
+			data.Scaling = append(data.Scaling, template.HTML(logLogChart(sc.Title, "n", "total time", lines, nil, fmtNs)))
+		}
+	}
+	data.Parallel, _ = parallelChart(sets)
+	data.WorkingSet, _ = workingSetChart(sets)
+	for _, s := range sets {
+		for _, r := range s.Latency {
+			title := r.Op
+			if len(sets) > 1 {
+				title = s.Label + " " + r.Op
+			}
+			data.Latency = append(data.Latency, histogramChart(title, r))
+		}
+	}
+	return reportTemplate.Execute(w, data)
+}
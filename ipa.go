@@ -0,0 +1,299 @@
+package mclbench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Bulletproofs-style inner-product argument over G1: for P = <a, G> + <b, H>
+// + <a, b> U with vectors of length 2^k, the prover sends k pairs (L, R) and
+// the two final scalars. Challenges come from hashing the transcript.
+
+// IPADomain separates the IPA generators from the Pedersen ones.
+var IPADomain = []byte("mclbench-ipa-v1")
+
+// IPAParams holds the generators G, H and U. Commit reuses the params' bases
+// and scalar buffer, so it must not run concurrently with the same params.
+type IPAParams struct {
+	G, H []mcl.G1 // bases[:n] and bases[n:2n]
+	U    mcl.G1
+
+	bases   []mcl.G1 // G, H, U
+	scalars []mcl.Fr
+}
+
+type IPAProof struct {
+	L, R []mcl.G1
+	A, B mcl.Fr
+}
+
+// Bytes is the size of the serialized proof.
+func (p *IPAProof) Bytes() int {
+	return (len(p.L)+len(p.R))*mcl.GetG1ByteSize() + 2*mcl.GetFrByteSize()
+}
+
+// NewIPAParams derives 2n + 1 generators by hash-to-curve.
+func NewIPAParams(n int) *IPAParams {
+	pp := &IPAParams{bases: make([]mcl.G1, 2*n+1), scalars: make([]mcl.Fr, 2*n+1)}
+	pp.G, pp.H = pp.bases[:n], pp.bases[n:2*n]
+	for i := 0; i < n; i++ {
+		pedersenGenerator(&pp.G[i], IPADomain, uint32(i))
+		pedersenGenerator(&pp.H[i], IPADomain, uint32(n+i))
+	}
+	pedersenGenerator(&pp.bases[2*n], IPADomain, uint32(2*n))
+	pp.U = pp.bases[2*n]
+	return pp
+}
+
+func innerProduct(a, b []mcl.Fr) mcl.Fr {
+	var sum, t mcl.Fr
+	for i := range a {
+		mcl.FrMul(&t, &a[i], &b[i])
+		mcl.FrAdd(&sum, &sum, &t)
+	}
+	return sum
+}
+
+// Commit sets P = <a, G> + <b, H> + <a, b> U; a and b have len(pp.G) elements.
+func (pp *IPAParams) Commit(P *mcl.G1, a, b []mcl.Fr) {
+	n := len(pp.G)
+	copy(pp.scalars, a[:n])
+	copy(pp.scalars[n:], b[:n])
+	pp.scalars[2*n] = innerProduct(a, b)
+	mcl.G1MulVec(P, pp.bases, pp.scalars)
+}
+
+// ipaChallenge hashes the previous challenge with L and R into x.
+func ipaChallenge(x *mcl.Fr, L, R *mcl.G1) {
+	buf := append(append(x.Serialize(), L.Serialize()...), R.Serialize()...)
+	x.SetHashOf(buf)
+}
+
+// foldG1 sets out[i] = lo*x[i] + hi*x[h+i] for the first half of x.
+func foldG1(x []mcl.G1, lo, hi *mcl.Fr) {
+	h := len(x) / 2
+	var t mcl.G1
+	for i := 0; i < h; i++ {
+		mcl.G1Mul(&x[i], &x[i], lo)
+		mcl.G1Mul(&t, &x[h+i], hi)
+		mcl.G1Add(&x[i], &x[i], &t)
+	}
+}
+
+// foldFr is foldG1 over Fr.
+func foldFr(x []mcl.Fr, lo, hi *mcl.Fr) {
+	h := len(x) / 2
+	var t mcl.Fr
+	for i := 0; i < h; i++ {
+		mcl.FrMul(&x[i], &x[i], lo)
+		mcl.FrMul(&t, &x[h+i], hi)
+		mcl.FrAdd(&x[i], &x[i], &t)
+	}
+}
+
+// Prove proves P = Commit(a, b); len(a) must be a power of two.
+func (pp *IPAParams) Prove(P *mcl.G1, a, b []mcl.Fr) *IPAProof {
+	n := len(a)
+	a = append([]mcl.Fr(nil), a...)
+	b = append([]mcl.Fr(nil), b...)
+	G := append([]mcl.G1(nil), pp.G[:n]...)
+	H := append([]mcl.G1(nil), pp.H[:n]...)
+	proof := &IPAProof{}
+
+	var x, xInv mcl.Fr
+	x.SetHashOf(P.Serialize())
+	bases := make([]mcl.G1, n+1)
+	scalars := make([]mcl.Fr, n+1)
+	for ; n > 1; n /= 2 {
+		h := n / 2
+		var L, R mcl.G1
+		copy(bases, G[h:n])
+		copy(bases[h:], H[:h])
+		bases[n] = pp.U
+		copy(scalars, a[:h])
+		copy(scalars[h:], b[h:n])
+		scalars[n] = innerProduct(a[:h], b[h:n])
+		mcl.G1MulVec(&L, bases[:n+1], scalars[:n+1])
+		copy(bases, G[:h])
+		copy(bases[h:], H[h:n])
+		copy(scalars, a[h:n])
+		copy(scalars[h:], b[:h])
+		scalars[n] = innerProduct(a[h:n], b[:h])
+		bases[n] = pp.U
+		mcl.G1MulVec(&R, bases[:n+1], scalars[:n+1])
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+
+		ipaChallenge(&x, &L, &R)
+		mcl.FrInv(&xInv, &x)
+		foldFr(a[:n], &x, &xInv)
+		foldFr(b[:n], &xInv, &x)
+		foldG1(G[:n], &xInv, &x)
+		foldG1(H[:n], &x, &xInv)
+	}
+	proof.A, proof.B = a[0], b[0]
+	return proof
+}
+
+// ipaChallenges replays the transcript of proof for P.
+func ipaChallenges(P *mcl.G1, proof *IPAProof) []mcl.Fr {
+	xs := make([]mcl.Fr, len(proof.L))
+	var x mcl.Fr
+	x.SetHashOf(P.Serialize())
+	for j := range xs {
+		ipaChallenge(&x, &proof.L[j], &proof.R[j])
+		xs[j] = x
+	}
+	return xs
+}
+
+// VerifyNaive folds the generators round by round as the prover does.
+func (pp *IPAParams) VerifyNaive(P *mcl.G1, proof *IPAProof) bool {
+	n := 1 << uint(len(proof.L))
+	G := append([]mcl.G1(nil), pp.G[:n]...)
+	H := append([]mcl.G1(nil), pp.H[:n]...)
+	acc := *P
+	var xInv, x2, t mcl.Fr
+	var T mcl.G1
+	for j, x := range ipaChallenges(P, proof) {
+		mcl.FrInv(&xInv, &x)
+		mcl.FrSqr(&x2, &x)
+		mcl.G1Mul(&T, &proof.L[j], &x2)
+		mcl.G1Add(&acc, &acc, &T)
+		mcl.FrSqr(&x2, &xInv)
+		mcl.G1Mul(&T, &proof.R[j], &x2)
+		mcl.G1Add(&acc, &acc, &T)
+		foldG1(G[:n], &xInv, &x)
+		foldG1(H[:n], &x, &xInv)
+		n /= 2
+	}
+	mcl.FrMul(&t, &proof.A, &proof.B)
+	var want mcl.G1
+	mcl.G1MulVec(&want, []mcl.G1{G[0], H[0], pp.U}, []mcl.Fr{proof.A, proof.B, t})
+	return want.IsEqual(&acc)
+}
+
+// Verify checks the whole proof with one G1MulVec of 2n + 2k + 2 terms, using
+// that the folded G is sum s_i G_i with s_i the product of x_j or 1/x_j
+// according to bit j of i, and the folded H uses 1/s_i.
+func (pp *IPAParams) Verify(P *mcl.G1, proof *IPAProof) bool {
+	k := len(proof.L)
+	n := 1 << uint(k)
+	xs := ipaChallenges(P, proof)
+	xInvs := make([]mcl.Fr, k)
+	for j := range xs {
+		mcl.FrInv(&xInvs[j], &xs[j])
+	}
+
+	s := make([]mcl.Fr, n)
+	sInv := make([]mcl.Fr, n)
+	s[0].SetInt64(1)
+	sInv[0].SetInt64(1)
+	for j, m := 0, 1; j < k; j, m = j+1, 2*m {
+		// round j is the next bit below the ones already expanded
+		for i := m - 1; i >= 0; i-- {
+			mcl.FrMul(&s[2*i+1], &s[i], &xs[j])
+			mcl.FrMul(&s[2*i], &s[i], &xInvs[j])
+			mcl.FrMul(&sInv[2*i+1], &sInv[i], &xInvs[j])
+			mcl.FrMul(&sInv[2*i], &sInv[i], &xs[j])
+		}
+	}
+
+	m := 2*n + 2*k + 2
+	bases := make([]mcl.G1, 0, m)
+	scalars := make([]mcl.Fr, m)
+	bases = append(append(bases, pp.G[:n]...), pp.H[:n]...)
+	for i := 0; i < n; i++ {
+		mcl.FrMul(&scalars[i], &proof.A, &s[i])
+		mcl.FrMul(&scalars[n+i], &proof.B, &sInv[i])
+	}
+	bases = append(bases, pp.U)
+	mcl.FrMul(&scalars[2*n], &proof.A, &proof.B)
+	for j := 0; j < k; j++ {
+		bases = append(bases, proof.L[j], proof.R[j])
+		mcl.FrSqr(&scalars[2*n+1+2*j], &xs[j])
+		mcl.FrNeg(&scalars[2*n+1+2*j], &scalars[2*n+1+2*j])
+		mcl.FrSqr(&scalars[2*n+2+2*j], &xInvs[j])
+		mcl.FrNeg(&scalars[2*n+2+2*j], &scalars[2*n+2+2*j])
+	}
+	bases = append(bases, *P)
+	scalars[m-1].SetInt64(-1)
+
+	var sum mcl.G1
+	mcl.G1MulVec(&sum, bases, scalars)
+	return sum.IsZero()
+}
+
+// IPA returns generators for vectors of 2^k elements, derived on first use.
+func (fx *Fixtures) IPA(k int) *IPAParams {
+	if fx.ipa == nil {
+		fx.ipa = make(map[int]*IPAParams)
+	}
+	pp, ok := fx.ipa[k]
+	if !ok {
+		pp = NewIPAParams(1 << uint(k))
+		fx.ipa[k] = pp
+	}
+	return pp
+}
+
+// ipaInputs returns vectors a and b of 2^k elements, cycling over fx.Fr.
+func ipaInputs(fx *Fixtures, k int) ([]mcl.Fr, []mcl.Fr) {
+	a := make([]mcl.Fr, 1<<uint(k))
+	b := make([]mcl.Fr, len(a))
+	for i := range a {
+		a[i] = fx.Fr[i%fx.Size()]
+		b[i] = fx.Fr[(i+1)%fx.Size()]
+	}
+	return a, b
+}
+
+func registerIPA() {
+
+	levels := []int{4, 6, 8, 10, 12}
+
+	for _, k := range levels {
+		k := k
+		Register(Case{Name: fmt.Sprintf("IPAProve_k%d", k), Group: "IPA", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var P mcl.G1
+			pp := fx.IPA(k)
+			a, b := ipaInputs(fx, k)
+			pp.Commit(&P, a, b)
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				pp.Prove(&P, a, b)
+			}
+			t.ReportMetric(float64(pp.Prove(&P, a, b).Bytes()), "bytes")
+		}})
+		Register(Case{Name: fmt.Sprintf("IPAVerifyNaive_k%d", k), Group: "IPA", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var P mcl.G1
+			pp := fx.IPA(k)
+			a, b := ipaInputs(fx, k)
+			pp.Commit(&P, a, b)
+			proof := pp.Prove(&P, a, b)
+			if !pp.VerifyNaive(&P, proof) {
+				t.Fatal("IPA proof does not verify")
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				pp.VerifyNaive(&P, proof)
+			}
+		}})
+		Register(Case{Name: fmt.Sprintf("IPAVerify_k%d", k), Group: "IPA", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var P mcl.G1
+			pp := fx.IPA(k)
+			a, b := ipaInputs(fx, k)
+			pp.Commit(&P, a, b)
+			proof := pp.Prove(&P, a, b)
+			if !pp.Verify(&P, proof) {
+				t.Fatal("IPA proof does not verify")
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				pp.Verify(&P, proof)
+			}
+		}})
+	}
+}
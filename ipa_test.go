@@ -0,0 +1,77 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// tamperedIPAProofs returns copies of proof with one of L, R, A or B changed.
+func tamperedIPAProofs(proof *IPAProof, g *mcl.G1) map[string]*IPAProof {
+	clone := func() *IPAProof {
+		p := *proof
+		p.L = append([]mcl.G1(nil), proof.L...)
+		p.R = append([]mcl.G1(nil), proof.R...)
+		return &p
+	}
+	var one mcl.Fr
+	one.SetInt64(1)
+	out := make(map[string]*IPAProof)
+	p := clone()
+	mcl.G1Add(&p.L[0], &p.L[0], g)
+	out["L"] = p
+	p = clone()
+	mcl.G1Add(&p.R[len(p.R)-1], &p.R[len(p.R)-1], g)
+	out["R"] = p
+	p = clone()
+	mcl.FrAdd(&p.A, &p.A, &one)
+	out["A"] = p
+	p = clone()
+	mcl.FrAdd(&p.B, &p.B, &one)
+	out["B"] = p
+	return out
+}
+
+func TestIPAVerifiers(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	for k := 1; k <= 4; k++ {
+		var P mcl.G1
+		pp := fx.IPA(k)
+		a, b := ipaInputs(fx, k)
+		pp.Commit(&P, a, b)
+		proof := pp.Prove(&P, a, b)
+		if len(proof.L) != k || len(proof.R) != k {
+			t.Fatalf("k=%d: proof has %d L and %d R, want %d", k, len(proof.L), len(proof.R), k)
+		}
+		if !pp.VerifyNaive(&P, proof) {
+			t.Errorf("k=%d: VerifyNaive rejects an honest proof", k)
+		}
+		if !pp.Verify(&P, proof) {
+			t.Errorf("k=%d: Verify rejects an honest proof", k)
+		}
+		for field, bad := range tamperedIPAProofs(proof, &fx.G1[0]) {
+			naive, fast := pp.VerifyNaive(&P, bad), pp.Verify(&P, bad)
+			if naive {
+				t.Errorf("k=%d: VerifyNaive accepts a proof with a tampered %s", k, field)
+			}
+			if fast != naive {
+				t.Errorf("k=%d, tampered %s: Verify = %v, VerifyNaive = %v", k, field, fast, naive)
+			}
+		}
+	}
+}
+
+func TestIPARejectsWrongCommitment(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	var P mcl.G1
+	pp := fx.IPA(3)
+	a, b := ipaInputs(fx, 3)
+	pp.Commit(&P, a, b)
+	proof := pp.Prove(&P, a, b)
+	mcl.G1Add(&P, &P, &fx.G1[0])
+	if pp.VerifyNaive(&P, proof) || pp.Verify(&P, proof) {
+		t.Error("a proof verifies against a different commitment")
+	}
+}
@@ -0,0 +1,129 @@
+package mclbench
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"time"
+)
+
+// Isolation runs every case in a fresh process, so that GC state, heap growth
+// and caches do not carry over from earlier cases. The child process runs the
+// case with RunChild and sends its results and warnings back over a pipe on
+// fd 3, which is not available on Windows.
+type Isolation struct {
+	// Command returns the command that runs this program in child mode for c,
+	// with the same curve, size and seed as the parent.
+	Command func(c Case) *exec.Cmd
+	Timeout time.Duration // kill a case after this long, 0 for no limit
+}
+
+// childResults is the descriptor the child writes its results to.
+const childResults = 3
+
+// childMessage is one line on the results pipe: a result or a warning.
+type childMessage struct {
+	Result  *Result `json:"result,omitempty"`
+	Warning string  `json:"warning,omitempty"`
+}
+
+// run times c in a child process, passing its results to emit and its
+// warnings to warn. A child that exits cleanly without any result is an error.
+func (iso *Isolation) run(c Case, emit func(Result), warn func(string)) error {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer pr.Close()
+	cmd := iso.Command(c)
+	cmd.ExtraFiles = []*os.File{pw} // becomes fd 3
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		return err
+	}
+	pw.Close()
+
+	var timedOut int32
+	if iso.Timeout > 0 {
+		t := time.AfterFunc(iso.Timeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			cmd.Process.Kill()
+		})
+		defer t.Stop()
+	}
+
+	results := 0
+	sc := bufio.NewScanner(pr)
+	sc.Buffer(make([]byte, 1<<20), 1<<20)
+	for sc.Scan() {
+		var msg childMessage
+		if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("bad result from child: %v", err)
+		}
+		if msg.Result != nil {
+			results++
+			emit(*msg.Result)
+		}
+		if msg.Warning != "" {
+			warn(msg.Warning)
+		}
+	}
+	err = cmd.Wait()
+	if atomic.LoadInt32(&timedOut) == 1 {
+		return fmt.Errorf("killed after %v", iso.Timeout)
+	}
+	if err == nil && results == 0 {
+		return fmt.Errorf("no results from the child process")
+	}
+	return err
+}
+
+// childReporter sends each result, and at the end each warning of the child's
+// run, to the parent as one JSON line.
+type childReporter struct {
+	enc *json.Encoder
+}
+
+func (p *childReporter) Start(run *Run) {}
+
+func (p *childReporter) Result(c Case, r Result) {
+	if err := p.enc.Encode(childMessage{Result: &r}); err != nil {
+		panic(err)
+	}
+}
+
+func (p *childReporter) Finish(run *Run) error {
+	for _, w := range run.Env.Warnings {
+		if err := p.enc.Encode(childMessage{Warning: w}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunChild is the child side of Isolation: it times the case named name with
+// cfg and writes the results and warnings to the parent's pipe.
+func RunChild(cfg Config, name string) error {
+	var c *Case
+	for _, r := range registry {
+		if r.Name == name {
+			r := r
+			c = &r
+		}
+	}
+	if c == nil {
+		return fmt.Errorf("no case %s", name)
+	}
+	out := os.NewFile(childResults, "results")
+	if out == nil {
+		return fmt.Errorf("child mode needs the results pipe on fd %d", childResults)
+	}
+	defer out.Close()
+	_, err := NewRunner(cfg, &childReporter{json.NewEncoder(out)}).Run([]Case{*c})
+	return err
+}
@@ -0,0 +1,254 @@
+package mclbench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/bits"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alinush/go-mcl"
+)
+
+// histSubBits sets the precision of a Histogram: every power of two is split
+// into 2^histSubBits linear sub-buckets, so values are kept within 1/128.
+const histSubBits = 7
+
+const histSub = 1 << histSubBits
+
+// Histogram is a log-linear histogram of latencies in ns, in the style of
+// HdrHistogram: constant relative precision over the whole int64 range.
+type Histogram struct {
+	counts []int64
+	n      int64
+	sum    float64
+	min    int64
+	max    int64
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make([]int64, (64-histSubBits)*histSub), min: math.MaxInt64}
+}
+
+func histIndex(v int64) int {
+	if v < histSub {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - histSubBits - 1
+	return (shift+1)*histSub + int(v>>uint(shift)) - histSub
+}
+
+// histBucket returns the lowest value and the width of bucket i.
+func histBucket(i int) (lo, width int64) {
+	if i < histSub {
+		return int64(i), 1
+	}
+	shift := uint(i/histSub - 1)
+	return int64(i%histSub+histSub) << shift, 1 << shift
+}
+
+func (h *Histogram) Record(ns int64) {
+	if ns < 0 {
+		ns = 0
+	}
+	h.counts[histIndex(ns)]++
+	h.n++
+	h.sum += float64(ns)
+	if ns < h.min {
+		h.min = ns
+	}
+	if ns > h.max {
+		h.max = ns
+	}
+}
+
+func (h *Histogram) Count() int64 { return h.n }
+
+func (h *Histogram) Max() int64 { return h.max }
+
+func (h *Histogram) Mean() float64 {
+	if h.n == 0 {
+		return 0
+	}
+	return h.sum / float64(h.n)
+}
+
+// Quantile returns the middle of the bucket holding the q-th quantile,
+// clamped to the recorded range.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h.n == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(h.n)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			lo, w := histBucket(i)
+			v := float64(lo) + float64(w-1)/2
+			return math.Max(float64(h.min), math.Min(v, float64(h.max)))
+		}
+	}
+	return float64(h.max)
+}
+
+type HistogramBucket struct {
+	Lo    int64 `json:"lo"` // ns, inclusive
+	Hi    int64 `json:"hi"` // ns, exclusive
+	Count int64 `json:"count"`
+}
+
+// Buckets returns the non-empty buckets in increasing order.
+func (h *Histogram) Buckets() []HistogramBucket {
+	var out []HistogramBucket
+	for i, c := range h.counts {
+		if c > 0 {
+			lo, w := histBucket(i)
+			out = append(out, HistogramBucket{lo, lo + w, c})
+		}
+	}
+	return out
+}
+
+// latencyQuantiles are reported for every operation, as <Op>_<name>-ns.
+var latencyQuantiles = []struct {
+	Name string
+	Q    float64
+}{{"p50", 0.5}, {"p90", 0.9}, {"p99", 0.99}, {"p99.9", 0.999}}
+
+type LatencyResult struct {
+	Op        string             `json:"op"`
+	Count     int64              `json:"count"`
+	Mean      float64            `json:"mean_ns"`
+	Quantiles map[string]float64 `json:"quantiles_ns"`
+	Max       float64            `json:"max_ns"`
+	Buckets   []HistogramBucket  `json:"buckets"`
+}
+
+func newLatencyResult(op string, h *Histogram) LatencyResult {
+	r := LatencyResult{Op: op, Count: h.Count(), Mean: h.Mean(), Max: float64(h.Max()), Buckets: h.Buckets(), Quantiles: make(map[string]float64)}
+	for _, q := range latencyQuantiles {
+		r.Quantiles[q.Name] = h.Quantile(q.Q)
+	}
+	return r
+}
+
+// latencyOp is one call on element j of the fixtures.
+type latencyOp struct {
+	name string
+	call func(j int)
+}
+
+func latencyOps(fx *Fixtures, msm int) []latencyOp {
+	var r1 mcl.G1
+	var r2 mcl.G2
+	var e mcl.GT
+	return []latencyOp{
+		{"G1Mul", func(j int) { mcl.G1Mul(&r1, &fx.G1[j], &fx.Fr[j]) }},
+		{"G2Mul", func(j int) { mcl.G2Mul(&r2, &fx.G2[j], &fx.Fr[j]) }},
+		{"MillerLoop", func(j int) { mcl.MillerLoop(&e, &fx.G1[j], &fx.G2[j]) }},
+		{"FinalExp", func(j int) { mcl.FinalExp(&e, &fx.GT[j]) }},
+		{"Pairing", func(j int) { mcl.Pairing(&e, &fx.G1[j], &fx.G2[j]) }},
+		{fmt.Sprintf("G1MulVec%d", msm), func(j int) { mcl.G1MulVec(&r1, fx.G1[:msm], fx.Fr[:msm]) }},
+	}
+}
+
+type LatencyConfig struct {
+	Ops      []string // operations to time, all if empty
+	N        int      // timed calls per operation
+	Warmup   int      // untimed calls per operation
+	MSMSize  int      // size of the G1MulVec operation, at most the fixture size
+	Progress io.Writer
+}
+
+// RunLatency times every selected operation call by call.
+// It fails before timing anything if an operation is unknown.
+func RunLatency(fx *Fixtures, cfg LatencyConfig) ([]LatencyResult, error) {
+	if cfg.MSMSize > fx.Size() {
+		cfg.MSMSize = fx.Size()
+	}
+	ops := latencyOps(fx, cfg.MSMSize)
+	known := map[string]bool{"G1MulVec": true}
+	for _, op := range ops {
+		known[op.name] = true
+	}
+	wanted := make(map[string]bool)
+	for _, s := range cfg.Ops {
+		if !known[s] {
+			return nil, fmt.Errorf("unknown operation %q", s)
+		}
+		wanted[s] = true
+	}
+	progress := cfg.Progress
+	if progress == nil {
+		progress = ioutil.Discard
+	}
+
+	var results []LatencyResult
+	for _, op := range ops {
+		if len(wanted) > 0 && !wanted[op.name] && !(wanted["G1MulVec"] && strings.HasPrefix(op.name, "G1MulVec")) {
+			continue
+		}
+		for i := 0; i < cfg.Warmup; i++ {
+			op.call(i % fx.Size())
+		}
+		h := NewHistogram()
+		for i := 0; i < cfg.N; i++ {
+			j := i % fx.Size()
+			start := time.Now()
+			op.call(j)
+			h.Record(time.Since(start).Nanoseconds())
+		}
+		r := newLatencyResult(op.name, h)
+		results = append(results, r)
+		fmt.Fprintf(progress, "%-12s n=%-8d mean=%12.3f us", r.Op, r.Count, r.Mean/1000)
+		for _, q := range latencyQuantiles {
+			fmt.Fprintf(progress, "  %s=%12.3f us", q.Name, r.Quantiles[q.Name]/1000)
+		}
+		fmt.Fprintf(progress, "  max=%12.3f us\n", r.Max/1000)
+	}
+	return results, nil
+}
+
+// LatencyValues flattens results into the results-file map as <Op>_<quantile>-ns.
+func LatencyValues(results []LatencyResult) map[string]float64 {
+	db := make(map[string]float64)
+	for _, r := range results {
+		db[r.Op] = r.Mean
+		for name, v := range r.Quantiles {
+			db[r.Op+"_"+name+"-ns"] = v
+		}
+		db[r.Op+"_max-ns"] = r.Max
+	}
+	return db
+}
+
+// HistogramPath is the file next to a results file that holds its latency histograms.
+func HistogramPath(results string) string {
+	return strings.TrimSuffix(results, filepath.Ext(results)) + ".hist.json"
+}
+
+func SaveLatency(path string, results []LatencyResult) error {
+	json, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, json, 0666)
+}
+
+func LoadLatency(path string) ([]LatencyResult, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []LatencyResult
+	err = json.Unmarshal(data, &results)
+	return results, err
+}
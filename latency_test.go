@@ -0,0 +1,66 @@
+package mclbench
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	tests := []struct {
+		v      int64
+		lo, hi int64
+	}{
+		{0, 0, 1},
+		{127, 127, 128},
+		{128, 128, 129},
+		{255, 255, 256},
+		{256, 256, 258},
+		{257, 256, 258},
+		{1000, 1000, 1004},
+		{1003, 1000, 1004},
+		{1004, 1004, 1008},
+	}
+	for _, tt := range tests {
+		lo, w := histBucket(histIndex(tt.v))
+		if lo != tt.lo || lo+w != tt.hi {
+			t.Errorf("%d is in [%d, %d), want [%d, %d)", tt.v, lo, lo+w, tt.lo, tt.hi)
+		}
+	}
+
+	h := NewHistogram()
+	for _, v := range []int64{255, 256, 257, -1} {
+		h.Record(v)
+	}
+	want := []HistogramBucket{{0, 1, 1}, {255, 256, 1}, {256, 258, 2}}
+	if got := h.Buckets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Buckets() = %v, want %v", got, want)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := NewHistogram()
+	if h.Quantile(0.5) != 0 || h.Mean() != 0 {
+		t.Errorf("empty histogram has p50 %v and mean %v, want 0", h.Quantile(0.5), h.Mean())
+	}
+	for v := int64(1); v <= 100; v++ {
+		h.Record(v)
+	}
+	h.Record(1000)
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{0, 1},
+		{0.5, 51},
+		{0.99, 100},
+		{1, 1000}, // bucket [1000, 1004) clamped to the max
+	}
+	for _, tt := range tests {
+		if got := h.Quantile(tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+	if h.Count() != 101 || h.Max() != 1000 || h.Mean() != 6050.0/101 {
+		t.Errorf("count %d, max %d, mean %v", h.Count(), h.Max(), h.Mean())
+	}
+}
@@ -0,0 +1,231 @@
+package mclbench
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/alinush/go-mcl"
+)
+
+// dudect-style timing leakage tests: single calls are timed in a random
+// interleaving of two input classes and compared with Welch's t-test.
+// |t| above LeakageThreshold means the timing depends on the class.
+const LeakageThreshold = 4.5
+
+// MinLeakageCheckpoint is the smallest checkpoint interval: with classes drawn
+// at random, fewer calls may leave a class, cropped or not, without samples.
+const MinLeakageCheckpoint = 100
+
+// welford keeps a running mean and variance.
+type welford struct {
+	n    float64
+	mean float64
+	m2   float64
+}
+
+func (w *welford) push(x float64) {
+	w.n++
+	d := x - w.mean
+	w.mean += d / w.n
+	w.m2 += d * (x - w.mean)
+}
+
+func (w *welford) variance() float64 {
+	if w.n < 2 {
+		return 0
+	}
+	return w.m2 / (w.n - 1)
+}
+
+// welchT is Welch's t-statistic, or 0 while a class has fewer than 2 samples.
+func welchT(a, b *welford) float64 {
+	if a.n < 2 || b.n < 2 {
+		return 0
+	}
+	den := math.Sqrt(a.variance()/a.n + b.variance()/b.n)
+	if den == 0 {
+		return 0
+	}
+	return (a.mean - b.mean) / den
+}
+
+// leakageOp times one secret-scalar operation.
+type leakageOp struct {
+	name string
+	run  func(k *mcl.Fr)
+}
+
+// leakageClasses fills a scalar for class 0 or 1.
+type leakageClasses struct {
+	name string
+	gen  func(class int, out *mcl.Fr)
+}
+
+type LeakagePoint struct {
+	N     int     `json:"n"`
+	T     float64 `json:"t"`
+	TCrop float64 `json:"t_cropped"`
+}
+
+type LeakageResult struct {
+	Op      string         `json:"op"`
+	Classes string         `json:"classes"`
+	MaxT    float64        `json:"max_abs_t"`
+	Leaks   bool           `json:"leaks"`
+	Series  []LeakagePoint `json:"series"`
+}
+
+func leakageOps() []leakageOp {
+	var P mcl.G1
+	var Q mcl.G2
+	var e mcl.GT
+	P.Random()
+	Q.Random()
+	mcl.Pairing(&e, &P, &Q)
+
+	var r1 mcl.G1
+	var r2 mcl.G2
+	var rT mcl.GT
+	return []leakageOp{
+		{"G1Mul", func(k *mcl.Fr) { mcl.G1Mul(&r1, &P, k) }},
+		{"G1MulCT", func(k *mcl.Fr) { mcl.G1MulCT(&r1, &P, k) }},
+		{"G2Mul", func(k *mcl.Fr) { mcl.G2Mul(&r2, &Q, k) }},
+		{"GTPow", func(k *mcl.Fr) { mcl.GTPow(&rT, &e, k) }},
+	}
+}
+
+// scalarWithDensity returns a scalar of bits bits, each set with probability p.
+func scalarWithDensity(rng *rand.Rand, bits int, p float64, out *mcl.Fr) {
+	buf := make([]byte, (bits+7)/8)
+	for i := 0; i < bits; i++ {
+		if rng.Float64() < p {
+			buf[i/8] |= 1 << uint(i%8)
+		}
+	}
+	out.SetLittleEndianMod(buf)
+}
+
+func leakageClassSets(rng *rand.Rand) []leakageClasses {
+	var order big.Int
+	order.SetString(mcl.GetCurveOrder(), 10)
+	// Stay one bit below the order so no reduction changes the weight.
+	bits := order.BitLen() - 1
+
+	var fixed mcl.Fr
+	fixed.Random()
+	return []leakageClasses{
+		{"fixed-vs-random", func(class int, out *mcl.Fr) {
+			if class == 0 {
+				*out = fixed
+			} else {
+				out.Random()
+			}
+		}},
+		{"lowHW-vs-highHW", func(class int, out *mcl.Fr) {
+			if class == 0 {
+				scalarWithDensity(rng, bits, 0.1, out)
+			} else {
+				scalarWithDensity(rng, bits, 0.9, out)
+			}
+		}},
+	}
+}
+
+// runLeakage measures op over n calls, reporting t every checkpoint calls.
+// The cropped test only keeps calls below the 90th percentile of a warm-up batch.
+func runLeakage(op leakageOp, cls leakageClasses, n int, checkpoint int, rng *rand.Rand, w io.Writer) LeakageResult {
+	const batch = 500
+	classes := make([]int, batch)
+	inputs := make([]mcl.Fr, batch)
+	timings := make([]float64, batch)
+
+	measure := func() {
+		for i := 0; i < batch; i++ {
+			classes[i] = rng.Intn(2)
+			cls.gen(classes[i], &inputs[i])
+		}
+		for i := 0; i < batch; i++ {
+			start := time.Now()
+			op.run(&inputs[i])
+			timings[i] = float64(time.Since(start).Nanoseconds())
+		}
+	}
+
+	measure()
+	warm := append([]float64(nil), timings...)
+	sort.Float64s(warm)
+	crop := warm[len(warm)*9/10]
+
+	var raw, cropped [2]welford
+	res := LeakageResult{Op: op.name, Classes: cls.name}
+	for done := 0; done < n; {
+		measure()
+		for i := 0; i < batch && done < n; i++ {
+			raw[classes[i]].push(timings[i])
+			if timings[i] < crop {
+				cropped[classes[i]].push(timings[i])
+			}
+			done++
+			if done%checkpoint == 0 || done == n {
+				pt := LeakagePoint{N: done, T: welchT(&raw[0], &raw[1]), TCrop: welchT(&cropped[0], &cropped[1])}
+				res.Series = append(res.Series, pt)
+				res.MaxT = math.Max(res.MaxT, math.Max(math.Abs(pt.T), math.Abs(pt.TCrop)))
+				fmt.Fprintf(w, "%-10s %-18s n=%-9d t=%9.3f  t_cropped=%9.3f\n", op.name, cls.name, pt.N, pt.T, pt.TCrop)
+			}
+		}
+	}
+	res.Leaks = res.MaxT > LeakageThreshold
+	return res
+}
+
+type LeakageConfig struct {
+	N          int      // measurements per operation and class pair
+	Checkpoint int      // record the t-statistic every this many measurements
+	Ops        []string // operations to test, all if empty
+	Seed       int64    // seed for class selection
+	Progress   io.Writer
+}
+
+// RunLeakage tests every selected operation against every class pair.
+// It fails before measuring anything if an operation is unknown or the
+// checkpoint interval is below MinLeakageCheckpoint.
+func RunLeakage(cfg LeakageConfig) ([]LeakageResult, error) {
+	if cfg.Checkpoint < MinLeakageCheckpoint {
+		return nil, fmt.Errorf("checkpoint %d is below %d", cfg.Checkpoint, MinLeakageCheckpoint)
+	}
+	ops := leakageOps()
+	known := make(map[string]bool)
+	for _, op := range ops {
+		known[op.name] = true
+	}
+	wanted := make(map[string]bool)
+	for _, s := range cfg.Ops {
+		if !known[s] {
+			return nil, fmt.Errorf("unknown operation %q", s)
+		}
+		wanted[s] = true
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	progress := cfg.Progress
+	if progress == nil {
+		progress = ioutil.Discard
+	}
+
+	var results []LeakageResult
+	for _, op := range ops {
+		if len(wanted) > 0 && !wanted[op.name] {
+			continue
+		}
+		for _, cls := range leakageClassSets(rng) {
+			results = append(results, runLeakage(op, cls, cfg.N, cfg.Checkpoint, rng, progress))
+		}
+		fmt.Fprintln(progress, SepString(""))
+	}
+	return results, nil
+}
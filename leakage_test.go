@@ -0,0 +1,26 @@
+package mclbench
+
+import "testing"
+
+func TestWelchTNeedsTwoSamplesPerClass(t *testing.T) {
+	var empty, one, many welford
+	one.push(10)
+	for _, x := range []float64{10, 12, 11, 13} {
+		many.push(x)
+	}
+	for _, tt := range []struct {
+		name string
+		a, b *welford
+	}{
+		{"empty", &empty, &many},
+		{"one sample", &many, &one},
+		{"both empty", &empty, &empty},
+	} {
+		if got := welchT(tt.a, tt.b); got != 0 {
+			t.Errorf("%s: welchT = %v, want 0", tt.name, got)
+		}
+	}
+	if got := welchT(&many, &many); got != 0 {
+		t.Errorf("welchT of identical classes = %v, want 0", got)
+	}
+}
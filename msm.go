@@ -0,0 +1,134 @@
+package mclbench
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Pure-Go bucket-method (Pippenger) multi-scalar multiplication built only
+// from G1Add, G1Dbl and G1Neg, to compare against mcl's native G1MulVec.
+
+// signedDigits recodes the little-endian scalar k into n signed c-bit digits
+// in [-2^(c-1), 2^(c-1)], which halves the number of buckets.
+func signedDigits(k []byte, c int, n int) []int {
+	digits := make([]int, n)
+	half := 1 << uint(c-1)
+	carry := 0
+	for i := 0; i < n; i++ {
+		d := scalarDigit(k, i, c) + carry
+		carry = 0
+		if d > half {
+			d -= 1 << uint(c)
+			carry = 1
+		}
+		digits[i] = d
+	}
+	return digits
+}
+
+func PippengerG1(out *mcl.G1, points []mcl.G1, scalars []mcl.Fr, c int) {
+	if len(points) != len(scalars) {
+		panic("points and scalars must have the same length")
+	}
+	n := numWindows(c) + 1
+	digits := make([][]int, len(scalars))
+	for j := range scalars {
+		digits[j] = signedDigits(scalars[j].Serialize(), c, n)
+	}
+
+	buckets := make([]mcl.G1, 1<<uint(c-1))
+	var neg, running, sum mcl.G1
+	out.Clear()
+	for w := n - 1; w >= 0; w-- {
+		for b := 0; b < c; b++ {
+			mcl.G1Dbl(out, out)
+		}
+		for b := range buckets {
+			buckets[b].Clear()
+		}
+		for j := range points {
+			d := digits[j][w]
+			if d > 0 {
+				mcl.G1Add(&buckets[d-1], &buckets[d-1], &points[j])
+			} else if d < 0 {
+				mcl.G1Neg(&neg, &points[j])
+				mcl.G1Add(&buckets[-d-1], &buckets[-d-1], &neg)
+			}
+		}
+		// sum_b (b+1) * buckets[b] via running sums
+		running.Clear()
+		sum.Clear()
+		for b := len(buckets) - 1; b >= 0; b-- {
+			mcl.G1Add(&running, &running, &buckets[b])
+			mcl.G1Add(&sum, &sum, &running)
+		}
+		mcl.G1Add(out, out, &sum)
+	}
+}
+
+// checkPippengerG1 compares PippengerG1 with mcl's G1MulVec.
+func checkPippengerG1(points []mcl.G1, scalars []mcl.Fr, c int) error {
+	var got, want mcl.G1
+	PippengerG1(&got, points, scalars, c)
+	mcl.G1MulVec(&want, points, scalars)
+	if !got.IsEqual(&want) {
+		return fmt.Errorf("PippengerG1 with c=%d over %d points differs from G1MulVec", c, len(points))
+	}
+	return nil
+}
+
+// pippengerBest records the fastest window per size as PippengerG1<n> and
+// PippengerG1<n>BestWindow.
+func pippengerBest(db map[string]float64, out map[string]float64) {
+	re := regexp.MustCompile(`^PippengerG1(\d+)_c(\d+)$`)
+	for k, v := range db {
+		m := re.FindStringSubmatch(k)
+		if m == nil {
+			continue
+		}
+		best := "PippengerG1" + m[1]
+		if cur, ok := out[best]; ok && cur <= v {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		c, _ := strconv.Atoi(m[2])
+		out[best] = v
+		out[best+"Avg"] = v / float64(n)
+		out[best+"BestWindow"] = float64(c)
+	}
+}
+
+func registerPippenger() {
+
+	windows := []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+	Register(Case{Name: "G1MulLoop", Group: "Pippenger", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result, tmp mcl.G1
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			result.Clear()
+			for j := 0; j < n; j++ {
+				mcl.G1Mul(&tmp, &fx.G1[j], &fx.Fr[j])
+				mcl.G1Add(&result, &result, &tmp)
+			}
+		}
+	}})
+	for _, c := range windows {
+		c := c
+		Register(Case{Name: fmt.Sprintf("PippengerG1{n}_c%d", c), Group: "Pippenger", Vector: true, Unit: "exp", Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var result mcl.G1
+			if err := checkPippengerG1(fx.G1[:n], fx.Fr[:n], c); err != nil {
+				t.Fatal(err)
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				PippengerG1(&result, fx.G1[:n], fx.Fr[:n], c)
+			}
+		}})
+	}
+	reducers = append(reducers, pippengerBest)
+}
@@ -0,0 +1,19 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func TestPippengerG1MatchesMulVec(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	for _, n := range []int{1, 2, 5, 64} {
+		for _, c := range []int{2, 4, 8, 12} {
+			if err := checkPippengerG1(fx.G1[:n], fx.Fr[:n], c); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package mclbench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Multilinear KZG (Papamanthou-Shi-Tamassia) over the boolean hypercube, as
+// used by Hyperproofs-style vector commitments. A vector of 2^l values is the
+// table of a multilinear polynomial f in l variables; the first variable is
+// the most significant bit of the index.
+
+// MultilinearSRS is a trapdoor setup for l variables.
+type MultilinearSRS struct {
+	L        int
+	Lagrange [][]mcl.G1 // Lagrange[k][b] = eq(tau over the last k variables, b) * G1
+	G1       mcl.G1
+	G2       mcl.G2
+	Tau      []mcl.G2 // tau_i * G2
+}
+
+func NewMultilinearSRS(l int, P *mcl.G1, Q *mcl.G2, tau []mcl.Fr) *MultilinearSRS {
+	srs := &MultilinearSRS{L: l, Lagrange: make([][]mcl.G1, l+1), G1: *P, G2: *Q, Tau: make([]mcl.G2, l)}
+	for i := range tau {
+		mcl.G2Mul(&srs.Tau[i], Q, &tau[i])
+	}
+
+	table := NewG1Table(P, 8)
+	eq := make([]mcl.Fr, 1)
+	eq[0].SetInt64(1)
+	var one, t mcl.Fr
+	one.SetInt64(1)
+	for k := 0; k <= l; k++ {
+		if k > 0 {
+			// prepend the variable tau[l-k] as the new top bit
+			next := make([]mcl.Fr, 2*len(eq))
+			mcl.FrSub(&t, &one, &tau[l-k])
+			for b := range eq {
+				mcl.FrMul(&next[b], &eq[b], &t)
+				mcl.FrMul(&next[len(eq)+b], &eq[b], &tau[l-k])
+			}
+			eq = next
+		}
+		pts := make([]mcl.G1, len(eq))
+		for b := range eq {
+			table.Mul(&pts[b], &eq[b])
+		}
+		srs.Lagrange[k] = make([]mcl.G1, len(eq))
+		NormalizeG1Batch(srs.Lagrange[k], pts)
+	}
+	return srs
+}
+
+// MLEEvaluate returns f(z) for the multilinear extension of the table f,
+// folding one variable at a time.
+func MLEEvaluate(f []mcl.Fr, z []mcl.Fr) mcl.Fr {
+	cur := append([]mcl.Fr(nil), f...)
+	var d mcl.Fr
+	for i := range z {
+		half := len(cur) / 2
+		for b := 0; b < half; b++ {
+			mcl.FrSub(&d, &cur[half+b], &cur[b])
+			mcl.FrMul(&d, &d, &z[i])
+			mcl.FrAdd(&cur[b], &cur[b], &d)
+		}
+		cur = cur[:half]
+	}
+	return cur[0]
+}
+
+func MultilinearCommit(out *mcl.G1, srs *MultilinearSRS, f []mcl.Fr) {
+	mcl.G1MulVec(out, srs.Lagrange[srs.L], f)
+}
+
+// MultilinearOpen returns v = f(z) and the l proofs of
+// f(X) - v = sum_i (X_i - z_i) q_i(X_{i+1}, ..., X_l), one G1MulVec each.
+func MultilinearOpen(srs *MultilinearSRS, f []mcl.Fr, z []mcl.Fr) ([]mcl.G1, mcl.Fr) {
+	proofs := make([]mcl.G1, srs.L)
+	cur := append([]mcl.Fr(nil), f...)
+	q := make([]mcl.Fr, len(f)/2)
+	var d mcl.Fr
+	for i := 0; i < srs.L; i++ {
+		half := len(cur) / 2
+		for b := 0; b < half; b++ {
+			mcl.FrSub(&q[b], &cur[half+b], &cur[b])
+			mcl.FrMul(&d, &q[b], &z[i])
+			mcl.FrAdd(&cur[b], &cur[b], &d)
+		}
+		mcl.G1MulVec(&proofs[i], srs.Lagrange[srs.L-1-i], q[:half])
+		cur = cur[:half]
+	}
+	return proofs, cur[0]
+}
+
+// MultilinearVerify checks e(C - v*G1, G2) = prod_i e(proof_i, (tau_i - z_i)*G2)
+// with one multi-pairing of l + 1 terms.
+func MultilinearVerify(srs *MultilinearSRS, C *mcl.G1, z []mcl.Fr, v *mcl.Fr, proofs []mcl.G1) bool {
+	ps := make([]mcl.G1, srs.L+1)
+	qs := make([]mcl.G2, srs.L+1)
+	mcl.G1Mul(&ps[0], &srs.G1, v)
+	mcl.G1Sub(&ps[0], C, &ps[0])
+	qs[0] = srs.G2
+	for i := 0; i < srs.L; i++ {
+		mcl.G1Neg(&ps[i+1], &proofs[i])
+		mcl.G2Mul(&qs[i+1], &srs.G2, &z[i])
+		mcl.G2Sub(&qs[i+1], &srs.Tau[i], &qs[i+1])
+	}
+	var e mcl.GT
+	mcl.MillerLoopVec(&e, ps, qs)
+	mcl.FinalExp(&e, &e)
+	return e.IsOne()
+}
+
+// MultilinearSRS returns a setup for l variables with tau taken from fx.Fr,
+// computed on first use.
+func (fx *Fixtures) MultilinearSRS(l int) *MultilinearSRS {
+	if fx.multilinearSRS == nil {
+		fx.multilinearSRS = make(map[int]*MultilinearSRS)
+	}
+	srs, ok := fx.multilinearSRS[l]
+	if !ok {
+		tau := make([]mcl.Fr, l)
+		for i := range tau {
+			tau[i] = fx.Fr[(i+1)%len(fx.Fr)]
+		}
+		srs = NewMultilinearSRS(l, &fx.G1[0], &fx.G2[0], tau)
+		fx.multilinearSRS[l] = srs
+	}
+	return srs
+}
+
+// multilinearInputs returns a table of 2^l values and a point, cycling over fx.Fr.
+func multilinearInputs(fx *Fixtures, l int) ([]mcl.Fr, []mcl.Fr) {
+	f := make([]mcl.Fr, 1<<uint(l))
+	for b := range f {
+		f[b] = fx.Fr[b%len(fx.Fr)]
+	}
+	z := make([]mcl.Fr, l)
+	for i := range z {
+		z[i] = fx.Fr[(i+len(fx.Fr)/2)%len(fx.Fr)]
+	}
+	return f, z
+}
+
+func registerMultilinear() {
+
+	levels := []int{4, 8, 12, 16}
+
+	for _, l := range levels {
+		l := l
+		Register(Case{Name: fmt.Sprintf("MLEEval_l%d", l), Group: "Multilinear", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			f, z := multilinearInputs(fx, l)
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				MLEEvaluate(f, z)
+			}
+		}})
+		Register(Case{Name: fmt.Sprintf("MLKZGCommit_l%d", l), Group: "Multilinear", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var result mcl.G1
+			srs := fx.MultilinearSRS(l)
+			f, _ := multilinearInputs(fx, l)
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				MultilinearCommit(&result, srs, f)
+			}
+		}})
+		Register(Case{Name: fmt.Sprintf("MLKZGOpen_l%d", l), Group: "Multilinear", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			srs := fx.MultilinearSRS(l)
+			f, z := multilinearInputs(fx, l)
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				MultilinearOpen(srs, f, z)
+			}
+			t.ReportMetric(float64(l*mcl.GetG1ByteSize()), "bytes")
+		}})
+		Register(Case{Name: fmt.Sprintf("MLKZGVerify_l%d", l), Group: "Multilinear", Single: true, Bench: func(t *testing.B, fx *Fixtures, n int) {
+			var C mcl.G1
+			srs := fx.MultilinearSRS(l)
+			f, z := multilinearInputs(fx, l)
+			MultilinearCommit(&C, srs, f)
+			proofs, v := MultilinearOpen(srs, f, z)
+			if !MultilinearVerify(srs, &C, z, &v, proofs) {
+				t.Fatal("multilinear KZG proof does not verify")
+			}
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				MultilinearVerify(srs, &C, z, &v, proofs)
+			}
+		}})
+	}
+}
@@ -0,0 +1,62 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// hypercubePoint returns the point of l variables for index b, the first
+// variable being the most significant bit.
+func hypercubePoint(l, b int) []mcl.Fr {
+	z := make([]mcl.Fr, l)
+	for i := range z {
+		z[i].SetInt64(int64(b >> uint(l-1-i) & 1))
+	}
+	return z
+}
+
+func TestMLEEvaluateOnHypercube(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	for _, l := range []int{1, 2, 4} {
+		f, _ := multilinearInputs(fx, l)
+		for b := range f {
+			if v := MLEEvaluate(f, hypercubePoint(l, b)); !v.IsEqual(&f[b]) {
+				t.Errorf("l=%d: f(%b) is not f[%d]", l, b, b)
+			}
+		}
+	}
+}
+
+func TestMultilinearOpenVerifies(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(64, 1)
+	for _, l := range []int{1, 2, 4} {
+		var C mcl.G1
+		srs := fx.MultilinearSRS(l)
+		f, z := multilinearInputs(fx, l)
+		MultilinearCommit(&C, srs, f)
+
+		proofs, v := MultilinearOpen(srs, f, z)
+		if want := MLEEvaluate(f, z); !v.IsEqual(&want) {
+			t.Errorf("l=%d: MultilinearOpen value is not MLEEvaluate", l)
+		}
+		if !MultilinearVerify(srs, &C, z, &v, proofs) {
+			t.Errorf("l=%d: an honest opening does not verify", l)
+		}
+
+		var one, wrong mcl.Fr
+		one.SetInt64(1)
+		mcl.FrAdd(&wrong, &v, &one)
+		if MultilinearVerify(srs, &C, z, &wrong, proofs) {
+			t.Errorf("l=%d: an opening verifies with a wrong value", l)
+		}
+
+		zb := hypercubePoint(l, len(f)-1)
+		proofs, v = MultilinearOpen(srs, f, zb)
+		if !v.IsEqual(&f[len(f)-1]) || !MultilinearVerify(srs, &C, zb, &v, proofs) {
+			t.Errorf("l=%d: the opening at a hypercube point does not verify", l)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mclbench
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Checks for conditions that make timings swing between runs: a CPU
+// governor other than "performance", turbo boost, system load and thermal
+// throttling. Each check is skipped when its /proc or sysfs file is missing.
+
+func governorWarning() string {
+	files, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/cpufreq/scaling_governor")
+	other := make(map[string]int)
+	for _, f := range files {
+		if g := readTrimmed(f); g != "" && g != "performance" {
+			other[g]++
+		}
+	}
+	var parts []string
+	for g, n := range other {
+		parts = append(parts, fmt.Sprintf("%s on %d of %d CPUs", g, n, len(files)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "frequency governor is " + strings.Join(parts, ", ")
+}
+
+func turboWarning() string {
+	if readTrimmed("/sys/devices/system/cpu/intel_pstate/no_turbo") == "0" ||
+		readTrimmed("/sys/devices/system/cpu/cpufreq/boost") == "1" {
+		return "turbo boost is enabled"
+	}
+	return ""
+}
+
+// loadAverage is the 1-minute load average, or -1 if unknown.
+func loadAverage() float64 {
+	f := strings.Fields(readTrimmed("/proc/loadavg"))
+	if len(f) == 0 {
+		return -1
+	}
+	v, err := strconv.ParseFloat(f[0], 64)
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+func loadWarning(maxLoad float64) string {
+	if l := loadAverage(); maxLoad > 0 && l > maxLoad {
+		return fmt.Sprintf("load average %.2f is above %.2f", l, maxLoad)
+	}
+	return ""
+}
+
+// throttleCount is the number of thermal throttling events since boot,
+// summed over all CPUs.
+func throttleCount() int64 {
+	files, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/thermal_throttle/*_throttle_count")
+	var total int64
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		total += n
+	}
+	return total
+}
+
+// NoiseWarnings returns the noisy conditions present now.
+func NoiseWarnings(maxLoad float64) []string {
+	var out []string
+	for _, w := range []string{governorWarning(), turboWarning(), loadWarning(maxLoad)} {
+		if w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
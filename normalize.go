@@ -0,0 +1,129 @@
+package mclbench
+
+import (
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Batch conversion of Jacobian points (x = X/Z^2, y = Y/Z^3, mcl's default
+// coordinates) to affine ones with Montgomery's trick: one field inversion
+// plus three multiplications per point instead of one inversion per point.
+
+// NormalizeG1Batch sets out[i] to the normalized in[i]; out and in may alias.
+func NormalizeG1Batch(out, in []mcl.G1) {
+	// prefix[i] is the product of the non-zero Z of in[:i].
+	prefix := make([]mcl.Fp, len(in)+1)
+	prefix[0].SetInt64(1)
+	for i := range in {
+		if in[i].Z.IsZero() {
+			prefix[i+1] = prefix[i]
+		} else {
+			mcl.FpMul(&prefix[i+1], &prefix[i], &in[i].Z)
+		}
+	}
+	var inv, zinv, zinv2, zinv3 mcl.Fp
+	mcl.FpInv(&inv, &prefix[len(in)])
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i].Z.IsZero() {
+			out[i] = in[i]
+			continue
+		}
+		mcl.FpMul(&zinv, &inv, &prefix[i])
+		mcl.FpMul(&inv, &inv, &in[i].Z)
+		mcl.FpSqr(&zinv2, &zinv)
+		mcl.FpMul(&zinv3, &zinv2, &zinv)
+		mcl.FpMul(&out[i].X, &in[i].X, &zinv2)
+		mcl.FpMul(&out[i].Y, &in[i].Y, &zinv3)
+		out[i].Z.SetInt64(1)
+	}
+}
+
+// NormalizeG2Batch is NormalizeG1Batch over Fp2.
+func NormalizeG2Batch(out, in []mcl.G2) {
+	prefix := make([]mcl.Fp2, len(in)+1)
+	prefix[0].D[0].SetInt64(1)
+	for i := range in {
+		if in[i].Z.IsZero() {
+			prefix[i+1] = prefix[i]
+		} else {
+			mcl.Fp2Mul(&prefix[i+1], &prefix[i], &in[i].Z)
+		}
+	}
+	var inv, zinv, zinv2, zinv3 mcl.Fp2
+	mcl.Fp2Inv(&inv, &prefix[len(in)])
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i].Z.IsZero() {
+			out[i] = in[i]
+			continue
+		}
+		mcl.Fp2Mul(&zinv, &inv, &prefix[i])
+		mcl.Fp2Mul(&inv, &inv, &in[i].Z)
+		mcl.Fp2Sqr(&zinv2, &zinv)
+		mcl.Fp2Mul(&zinv3, &zinv2, &zinv)
+		mcl.Fp2Mul(&out[i].X, &in[i].X, &zinv2)
+		mcl.Fp2Mul(&out[i].Y, &in[i].Y, &zinv3)
+		out[i].Z.D[0].SetInt64(1)
+		out[i].Z.D[1].Clear()
+	}
+}
+
+// checkNormalizeG1Batch compares NormalizeG1Batch with G1Normalize point by
+// point, coordinate by coordinate.
+func checkNormalizeG1Batch(in []mcl.G1) error {
+	got := make([]mcl.G1, len(in))
+	NormalizeG1Batch(got, in)
+	want := affineG1(in)
+	for i := range in {
+		if !got[i].X.IsEqual(&want[i].X) || !got[i].Y.IsEqual(&want[i].Y) || !got[i].Z.IsEqual(&want[i].Z) {
+			return fmt.Errorf("NormalizeG1Batch differs from G1Normalize at point %d", i)
+		}
+	}
+	return nil
+}
+
+func checkNormalizeG2Batch(in []mcl.G2) error {
+	got := make([]mcl.G2, len(in))
+	NormalizeG2Batch(got, in)
+	want := affineG2(in)
+	for i := range in {
+		if !got[i].X.IsEqual(&want[i].X) || !got[i].Y.IsEqual(&want[i].Y) || !got[i].Z.IsEqual(&want[i].Z) {
+			return fmt.Errorf("NormalizeG2Batch differs from G2Normalize at point %d", i)
+		}
+	}
+	return nil
+}
+
+// jacobianG1 returns points with Z != 1: the doubles of pts.
+func jacobianG1(pts []mcl.G1) []mcl.G1 {
+	out := make([]mcl.G1, len(pts))
+	for i := range pts {
+		mcl.G1Dbl(&out[i], &pts[i])
+	}
+	return out
+}
+
+func jacobianG2(pts []mcl.G2) []mcl.G2 {
+	out := make([]mcl.G2, len(pts))
+	for i := range pts {
+		mcl.G2Dbl(&out[i], &pts[i])
+	}
+	return out
+}
+
+// affineG1 returns normalized copies of pts.
+func affineG1(pts []mcl.G1) []mcl.G1 {
+	out := make([]mcl.G1, len(pts))
+	for i := range pts {
+		mcl.G1Normalize(&out[i], &pts[i])
+	}
+	return out
+}
+
+func affineG2(pts []mcl.G2) []mcl.G2 {
+	out := make([]mcl.G2, len(pts))
+	for i := range pts {
+		mcl.G2Normalize(&out[i], &pts[i])
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func TestNormalizeG1BatchMatchesNormalize(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(16, 1)
+	pts := jacobianG1(fx.G1)
+	// points at infinity at both ends and in the middle, and affine inputs
+	pts[0].Clear()
+	pts[7].Clear()
+	pts[15].Clear()
+	pts[3] = fx.G1[3]
+	for _, n := range []int{1, 2, 5, 16} {
+		if err := checkNormalizeG1Batch(pts[:n]); err != nil {
+			t.Errorf("n=%d: %v", n, err)
+		}
+		if err := checkNormalizeG1Batch(pts[1:n]); err != nil {
+			t.Errorf("n=%d without the leading zero: %v", n, err)
+		}
+	}
+
+	inPlace := append([]mcl.G1(nil), pts...)
+	NormalizeG1Batch(inPlace, inPlace)
+	want := affineG1(pts)
+	for i := range want {
+		if !inPlace[i].IsEqual(&want[i]) || !inPlace[i].Z.IsEqual(&want[i].Z) {
+			t.Errorf("in-place NormalizeG1Batch differs from G1Normalize at point %d", i)
+		}
+	}
+}
+
+func TestNormalizeG2BatchMatchesNormalize(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(16, 1)
+	pts := jacobianG2(fx.G2)
+	pts[0].Clear()
+	pts[7].Clear()
+	pts[15].Clear()
+	pts[3] = fx.G2[3]
+	for _, n := range []int{1, 2, 5, 16} {
+		if err := checkNormalizeG2Batch(pts[:n]); err != nil {
+			t.Errorf("n=%d: %v", n, err)
+		}
+		if err := checkNormalizeG2Batch(pts[1:n]); err != nil {
+			t.Errorf("n=%d without the leading zero: %v", n, err)
+		}
+	}
+
+	inPlace := append([]mcl.G2(nil), pts...)
+	NormalizeG2Batch(inPlace, inPlace)
+	want := affineG2(pts)
+	for i := range want {
+		if !inPlace[i].IsEqual(&want[i]) || !inPlace[i].Z.IsEqual(&want[i].Z) {
+			t.Errorf("in-place NormalizeG2Batch differs from G2Normalize at point %d", i)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func registerPairing() {
+
+	Register(Case{Name: "GTMul", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.GT
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTMul(&result, &result, &fx.GT[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "GTPow", Group: "GT", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.GT
+		result.SetString("1", 10)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.GTPow(&result, &fx.GT[j], &fx.Fr[j])
+			}
+		}
+	}})
+	registerGT()
+	// =============================================
+	Register(Case{Name: "FinalExp", Group: "MillerLoop", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		result := make([]mcl.GT, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.FinalExp(&result[j], &fx.GT[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "MillerLoop", Group: "MillerLoop", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		result := make([]mcl.GT, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.MillerLoop(&result[j], &fx.G1[j], &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "MillerLoopVec", Group: "MillerLoop", Vector: true, Unit: "MillerLoop", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.GT
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			mcl.MillerLoopVec(&result, fx.G1[:n], fx.G2[:n])
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "Pairing", Group: "Pairing", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		result := make([]mcl.GT, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.Pairing(&result[j], &fx.G1[j], &fx.G2[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "MultiPairing", Group: "Pairing", Vector: true, Unit: "pairing", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.GT
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			mcl.MillerLoopVec(&result, fx.G1[:n], fx.G2[:n])
+			mcl.FinalExp(&result, &result)
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "FrIsEqual", Group: "IsEqual", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var a mcl.Fr
+		a.Random()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			a.IsEqual(&fx.Fr[0])
+			for j := 0; j < n-1; j++ {
+				fx.Fr[j].IsEqual(&fx.Fr[j+1])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G1IsEqual", Group: "IsEqual", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var a mcl.G1
+		a.Random()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			a.IsEqual(&fx.G1[0])
+			for j := 0; j < n-1; j++ {
+				fx.G1[j].IsEqual(&fx.G1[j+1])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "G2IsEqual", Group: "IsEqual", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var a mcl.G2
+		a.Random()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			a.IsEqual(&fx.G2[0])
+			for j := 0; j < n-1; j++ {
+				fx.G2[j].IsEqual(&fx.G2[j+1])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "GTIsEqual", Group: "IsEqual", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var a mcl.GT
+		a.SetInt64(1)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			a.IsEqual(&fx.GT[0])
+			for j := 0; j < n-1; j++ {
+				fx.GT[j].IsEqual(&fx.GT[j+1])
+			}
+		}
+	}})
+}
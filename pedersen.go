@@ -0,0 +1,186 @@
+package mclbench
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Pedersen commitments over G1 with generators derived by hash-to-curve, so
+// nobody knows their discrete logarithms.
+
+// PedersenDomain separates the generators of this suite from other uses of
+// hash-to-curve.
+var PedersenDomain = []byte("mclbench-pedersen-v1")
+
+// PedersenParams holds the message generators G and the blinding generator H.
+// Commitments reuse the params' bases and scalar buffer, so they must not be
+// computed concurrently with the same params.
+type PedersenParams struct {
+	G []mcl.G1 // bases[1:]
+	H mcl.G1
+
+	bases   []mcl.G1 // H, G[0], G[1], ...: the bases of every prefix of G are contiguous
+	scalars []mcl.Fr
+}
+
+// pedersenGenerator hashes domain || index to G1.
+func pedersenGenerator(out *mcl.G1, domain []byte, index uint32) {
+	buf := make([]byte, len(domain)+4)
+	copy(buf, domain)
+	binary.LittleEndian.PutUint32(buf[len(domain):], index)
+	if err := out.HashAndMapTo(buf); err != nil {
+		panic(err)
+	}
+}
+
+// pedersenBlindingIndex is reserved for H, so that H does not depend on the
+// number of message generators and is no G[i] of a larger parameter set.
+const pedersenBlindingIndex = math.MaxUint32
+
+// NewPedersenParams derives n message generators and the blinding one.
+func NewPedersenParams(n int, domain []byte) *PedersenParams {
+	pp := &PedersenParams{bases: make([]mcl.G1, n+1), scalars: make([]mcl.Fr, n+1)}
+	pp.G = pp.bases[1:]
+	for i := range pp.G {
+		pedersenGenerator(&pp.G[i], domain, uint32(i))
+	}
+	pedersenGenerator(&pp.bases[0], domain, pedersenBlindingIndex)
+	pp.H = pp.bases[0]
+	return pp
+}
+
+// Commit sets out = m*G[0] + r*H.
+func (pp *PedersenParams) Commit(out *mcl.G1, m, r *mcl.Fr) {
+	pp.scalars[0], pp.scalars[1] = *r, *m
+	mcl.G1MulVec(out, pp.bases[:2], pp.scalars[:2])
+}
+
+// CommitVector sets out = sum m[i]*G[i] + r*H with one G1MulVec.
+func (pp *PedersenParams) CommitVector(out *mcl.G1, m []mcl.Fr, r *mcl.Fr) {
+	pp.scalars[0] = *r
+	copy(pp.scalars[1:], m)
+	mcl.G1MulVec(out, pp.bases[:len(m)+1], pp.scalars[:len(m)+1])
+}
+
+// Verify recomputes the commitment to (m, r) and compares it with C.
+func (pp *PedersenParams) Verify(C *mcl.G1, m, r *mcl.Fr) bool {
+	var want mcl.G1
+	pp.Commit(&want, m, r)
+	return want.IsEqual(C)
+}
+
+// BatchVerify checks the openings (m[j], r[j]) of C[j] together: for random
+// rho, sum rho_j C_j - (sum rho_j m_j) G[0] - (sum rho_j r_j) H must be zero,
+// which is one G1MulVec of len(C) + 2 terms.
+func (pp *PedersenParams) BatchVerify(C []mcl.G1, m, r []mcl.Fr) bool {
+	n := len(C)
+	bases := make([]mcl.G1, n+2)
+	scalars := make([]mcl.Fr, n+2)
+	copy(bases, C)
+	mcl.G1Neg(&bases[n], &pp.G[0])
+	mcl.G1Neg(&bases[n+1], &pp.H)
+	var t mcl.Fr
+	for j := 0; j < n; j++ {
+		scalars[j].SetByCSPRNG()
+		mcl.FrMul(&t, &scalars[j], &m[j])
+		mcl.FrAdd(&scalars[n], &scalars[n], &t)
+		mcl.FrMul(&t, &scalars[j], &r[j])
+		mcl.FrAdd(&scalars[n+1], &scalars[n+1], &t)
+	}
+	var sum mcl.G1
+	mcl.G1MulVec(&sum, bases, scalars)
+	return sum.IsZero()
+}
+
+// Pedersen returns generators for every fixture element, derived on first use.
+func (fx *Fixtures) Pedersen() *PedersenParams {
+	if fx.pedersen == nil {
+		fx.pedersen = NewPedersenParams(fx.Size(), PedersenDomain)
+	}
+	return fx.pedersen
+}
+
+// pedersenOpenings commits to fx.Fr[j] with blinding fx.Fr[j+1].
+func pedersenOpenings(fx *Fixtures, n int) ([]mcl.G1, []mcl.Fr, []mcl.Fr) {
+	pp := fx.Pedersen()
+	C := make([]mcl.G1, n)
+	m := make([]mcl.Fr, n)
+	r := make([]mcl.Fr, n)
+	for j := 0; j < n; j++ {
+		m[j] = fx.Fr[j]
+		r[j] = fx.Fr[(j+1)%fx.Size()]
+		pp.Commit(&C[j], &m[j], &r[j])
+	}
+	return C, m, r
+}
+
+func registerPedersen() {
+
+	Register(Case{Name: "PedersenGenerators", Group: "Pedersen", Vector: true, Unit: "generator", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			NewPedersenParams(n, PedersenDomain)
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PedersenCommit", Group: "Pedersen", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		pp := fx.Pedersen()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				pp.Commit(&result, &fx.Fr[j], &fx.Fr[n-1-j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PedersenVectorCommit", Group: "Pedersen", Vector: true, Unit: "msg", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.G1
+		pp := fx.Pedersen()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			pp.CommitVector(&result, fx.Fr[:n], &fx.Fr[n-1])
+		}
+	}})
+	// =============================================
+	// Adds commitments and their openings, as when aggregating.
+	Register(Case{Name: "PedersenAdd", Group: "Pedersen", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var C mcl.G1
+		var m, r mcl.Fr
+		Cs, ms, rs := pedersenOpenings(fx, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.G1Add(&C, &C, &Cs[j])
+				mcl.FrAdd(&m, &m, &ms[j])
+				mcl.FrAdd(&r, &r, &rs[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PedersenVerify", Group: "Pedersen", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		pp := fx.Pedersen()
+		C, m, r := pedersenOpenings(fx, n)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				pp.Verify(&C[j], &m[j], &r[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PedersenBatchVerify", Group: "Pedersen", Vector: true, Unit: "opening", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		pp := fx.Pedersen()
+		C, m, r := pedersenOpenings(fx, n)
+		if !pp.BatchVerify(C, m, r) {
+			t.Fatal("Pedersen openings do not verify")
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			pp.BatchVerify(C, m, r)
+		}
+	}})
+}
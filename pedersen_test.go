@@ -0,0 +1,74 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func TestPedersenCommitVector(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(16, 1)
+	pp := fx.Pedersen()
+
+	var single, vector mcl.G1
+	pp.Commit(&single, &fx.Fr[0], &fx.Fr[1])
+	pp.CommitVector(&vector, fx.Fr[:1], &fx.Fr[1])
+	if !single.IsEqual(&vector) {
+		t.Error("CommitVector of one message differs from Commit")
+	}
+
+	var want, term mcl.G1
+	mcl.G1Mul(&want, &pp.H, &fx.Fr[15])
+	for i := 0; i < 8; i++ {
+		mcl.G1Mul(&term, &pp.G[i], &fx.Fr[i])
+		mcl.G1Add(&want, &want, &term)
+	}
+	pp.CommitVector(&vector, fx.Fr[:8], &fx.Fr[15])
+	if !vector.IsEqual(&want) {
+		t.Error("CommitVector differs from sum m[i]*G[i] + r*H")
+	}
+}
+
+func TestPedersenBlindingGenerator(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	small, large := NewPedersenParams(4, PedersenDomain), NewPedersenParams(8, PedersenDomain)
+	if !small.H.IsEqual(&large.H) {
+		t.Error("H depends on the number of message generators")
+	}
+	for i := range large.G {
+		if large.G[i].IsEqual(&small.H) {
+			t.Errorf("H is G[%d] of a larger parameter set", i)
+		}
+	}
+}
+
+func TestPedersenBatchVerify(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+	fx := NewSeededFixtures(16, 1)
+	pp := fx.Pedersen()
+	C, m, r := pedersenOpenings(fx, 8)
+	for j := range C {
+		if !pp.Verify(&C[j], &m[j], &r[j]) {
+			t.Errorf("opening %d does not verify", j)
+		}
+	}
+	if !pp.BatchVerify(C, m, r) {
+		t.Error("BatchVerify rejects honest openings")
+	}
+
+	var one mcl.Fr
+	one.SetInt64(1)
+	for _, j := range []int{0, 7} {
+		bad := append([]mcl.Fr(nil), m...)
+		mcl.FrAdd(&bad[j], &bad[j], &one)
+		if pp.BatchVerify(C, bad, r) {
+			t.Errorf("BatchVerify accepts a tampered m[%d]", j)
+		}
+		bad = append([]mcl.Fr(nil), r...)
+		mcl.FrAdd(&bad[j], &bad[j], &one)
+		if pp.BatchVerify(C, m, bad) {
+			t.Errorf("BatchVerify accepts a tampered r[%d]", j)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package mclbench
+
+import (
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// precomputedMillerLoopProduct multiplies n separate Miller loops over
+// precomputed G2 line coefficients. It is not a multi-Miller loop with a shared
+// accumulator like MillerLoopVec: the binding has no such API, and
+// mcl.PrecomputedMillerLoop2 passes P1/Q1buf twice and never reads P2/Q2buf.
+func precomputedMillerLoopProduct(out *mcl.GT, xVec []mcl.G1, qBufs [][]uint64) {
+	var tmp mcl.GT
+	mcl.PrecomputedMillerLoop(out, &xVec[0], qBufs[0])
+	for j := 1; j < len(xVec); j++ {
+		mcl.PrecomputedMillerLoop(&tmp, &xVec[j], qBufs[j])
+		mcl.GTMul(out, out, &tmp)
+	}
+}
+
+func registerPrecomputedPairing() {
+
+	Register(Case{Name: "PrecomputeG2", Group: "Precomputed", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		bufLen := mcl.GetUint64NumToPrecompute()
+		qBufs := make([][]uint64, n)
+		for j := range qBufs {
+			qBufs[j] = make([]uint64, bufLen)
+		}
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.PrecomputeG2(qBufs[j], &fx.G2[j])
+			}
+		}
+		t.ReportMetric(float64(bufLen*8), "bytes")
+	}})
+	// =============================================
+	Register(Case{Name: "PrecomputedMillerLoop", Group: "Precomputed", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		result := make([]mcl.GT, n)
+		qBufs := fx.G2Precomputed()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.PrecomputedMillerLoop(&result[j], &fx.G1[j], qBufs[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PrecomputedPairing", Group: "Precomputed", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		result := make([]mcl.GT, n)
+		qBufs := fx.G2Precomputed()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				mcl.PrecomputedMillerLoop(&result[j], &fx.G1[j], qBufs[j])
+				mcl.FinalExp(&result[j], &result[j])
+			}
+		}
+	}})
+	// =============================================
+	Register(Case{Name: "PrecomputedMillerLoopProduct", Group: "Precomputed", Vector: true, Unit: "MillerLoop", Bench: func(t *testing.B, fx *Fixtures, n int) {
+		var result mcl.GT
+		qBufs := fx.G2Precomputed()
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			precomputedMillerLoopProduct(&result, fx.G1[:n], qBufs[:n])
+		}
+	}})
+}
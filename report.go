@@ -0,0 +1,129 @@
+package mclbench
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// A Reporter receives the results of a run as they are produced.
+type Reporter interface {
+	Start(run *Run)
+	Result(c Case, r Result)
+	Finish(run *Run) error
+}
+
+func SepString(in string) string {
+	return fmt.Sprintf("%s=============================================", in)
+}
+
+func Summary(size uint64, op string, aux string, r *testing.BenchmarkResult) {
+
+	// a := time.Duration(r.NsPerOp() / int64(size))
+	// out := fmt.Sprintf("Time per %s (%d iters%s):", op, r.N, aux)
+	// fmt.Printf("%-60s %20v\n", out, a)
+
+	summaryLine(os.Stdout, size, op, aux, r.N, float64(r.NsPerOp()))
+}
+
+func summaryLine(w io.Writer, size uint64, op string, aux string, iters int, nsPerOp float64) {
+	p := message.NewPrinter(language.English)
+	a := nsPerOp / float64(size) / float64(1000) // Convert ns to us
+	out := fmt.Sprintf("Time per %s (%s%d iters):", op, aux, iters)
+	p.Fprintf(w, "%-60s %20.3f us\n", out, a)
+}
+
+// TextReporter prints the per-case summary lines, one block per group.
+type TextReporter struct {
+	W     io.Writer
+	group string
+}
+
+func NewTextReporter(w io.Writer) *TextReporter {
+	return &TextReporter{W: w}
+}
+
+func (t *TextReporter) Start(run *Run) {
+	fmt.Fprintf(t.W, "Curve: %s\n", run.Curve)
+	if run.Env != nil {
+		for _, l := range run.Env.Lines() {
+			fmt.Fprintln(t.W, l)
+		}
+	}
+	fmt.Fprintln(t.W, SepString(""))
+}
+
+func (t *TextReporter) Result(c Case, r Result) {
+	if t.group != "" && t.group != c.Group {
+		fmt.Fprintln(t.W, SepString(""))
+	}
+	t.group = c.Group
+
+	procs := ""
+	if r.Key != c.Key(r.Size) {
+		procs = fmt.Sprintf("GOMAXPROCS %d; ", r.Procs)
+	}
+	if gcs := r.Extra[gcCountUnit]; gcs > 0 {
+		procs += fmt.Sprintf("%.0f GCs, %.1f us paused; ", gcs, r.Extra[gcPauseUnit])
+	}
+	if c.Vector {
+		summaryLine(t.W, 1, c.Label(), fmt.Sprintf("%ssize %s; ", procs, humanize.Comma(int64(r.Size))), r.Iterations, r.NsPerOp)
+		summaryLine(t.W, uint64(r.Size), c.Label(), fmt.Sprintf("%sper %s; ", procs, c.Unit), r.Iterations, r.NsPerOp)
+	} else {
+		summaryLine(t.W, uint64(r.Size), c.Label(), procs, r.Iterations, r.NsPerOp)
+	}
+	units := make([]string, 0, len(r.Extra))
+	for unit := range r.Extra {
+		if unit != gcCountUnit && unit != gcPauseUnit {
+			units = append(units, unit)
+		}
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		fmt.Fprintf(t.W, "%-60s %20.3f %s\n", fmt.Sprintf("%s %s:", r.Key, unit), r.Extra[unit], unit)
+	}
+}
+
+func (t *TextReporter) Finish(run *Run) error {
+	fmt.Fprintln(t.W, SepString(""))
+	keys := make([]string, 0, len(run.Derived))
+	for k := range run.Derived {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(t.W, "%-60s %20.3f\n", k+":", run.Derived[k])
+	}
+	fmt.Fprintln(t.W, SepString(""))
+	if run.Env != nil {
+		for _, w := range run.Env.Warnings {
+			fmt.Fprintln(t.W, "WARNING:", w)
+		}
+	}
+	return nil
+}
+
+// JSONReporter writes the flattened results (see Run.Values) to Path and the
+// environment to EnvPath(Path).
+type JSONReporter struct {
+	Path string
+}
+
+func (j *JSONReporter) Start(run *Run) {}
+
+func (j *JSONReporter) Result(c Case, r Result) {}
+
+func (j *JSONReporter) Finish(run *Run) error {
+	if run.Env != nil {
+		if err := SaveEnvironment(EnvPath(j.Path), run.Env); err != nil {
+			return err
+		}
+	}
+	return SaveResults(j.Path, run.Values())
+}
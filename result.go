@@ -0,0 +1,77 @@
+package mclbench
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// Result is one timed case at one size.
+type Result struct {
+	Name       string             `json:"name"`
+	Group      string             `json:"group"`
+	Key        string             `json:"key"`
+	Vector     bool               `json:"vector,omitempty"`
+	Size       int                `json:"size"`
+	Iterations int                `json:"iterations"`
+	NsPerOp    float64            `json:"ns_per_op"`
+	Extra      map[string]float64 `json:"extra,omitempty"`
+	Procs      int                `json:"gomaxprocs,omitempty"`
+	CPUs       []int              `json:"cpus,omitempty"` // pinned CPUs
+}
+
+// PerElement is the cost of one element: one loop step of a per-element case
+// or one entry of a vector case.
+func (r Result) PerElement() float64 {
+	return r.NsPerOp / float64(r.Size)
+}
+
+// Run is everything recorded by one invocation of the suite.
+type Run struct {
+	Curve   string             `json:"curve"`
+	Started time.Time          `json:"started"`
+	Planned int                `json:"planned"` // number of results the run will record
+	Env     *Environment       `json:"env,omitempty"`
+	Results []Result           `json:"results"`
+	Derived map[string]float64 `json:"derived,omitempty"`
+}
+
+// Values flattens the run into the results-file map: per-element cases under
+// their name, vector cases as Name<n> and Name<n>Avg, extra metrics as
+// Key_unit, and the derived metrics.
+func (run *Run) Values() map[string]float64 {
+	db := make(map[string]float64)
+	for _, r := range run.Results {
+		if r.Vector {
+			db[r.Key] = r.NsPerOp
+			db[r.Key+"Avg"] = r.PerElement()
+		} else {
+			db[r.Key] = r.PerElement()
+		}
+		for unit, v := range r.Extra {
+			db[r.Key+"_"+unit] = v
+		}
+	}
+	for k, v := range run.Derived {
+		db[k] = v
+	}
+	return db
+}
+
+func SaveResults(path string, db map[string]float64) error {
+	json, err := json.Marshal(db)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, json, 0666)
+}
+
+func LoadResults(path string) (map[string]float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var db map[string]float64
+	err = json.Unmarshal(data, &db)
+	return db, err
+}
@@ -0,0 +1,231 @@
+package mclbench
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Config selects the curve and fixture sizes of a run.
+type Config struct {
+	Curve string
+	Size  uint64 // elements per fixture slice
+	Sweep []int  // sizes of the vector cases, capped at Size
+
+	Warmup  int     // untimed runs of each case before it is timed
+	MaxLoad float64 // warn when the 1-minute load average is above this, 0 to ignore
+	Strict  bool    // refuse to run when any noise check fails
+
+	CPUs  []int // pin the timed goroutine's OS thread to these CPUs, if set
+	Procs []int // GOMAXPROCS values to run every case with; keys get _p<N> when there are several
+
+	Seed int64 // fixtures are derived from this seed when non-zero, and random otherwise
+
+	DisableGC bool // collect before each round of a case and disable the GC up to NoGCHeadroom
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Curve: "bls12-381",
+		Size:  1000,
+		Sweep: []int{2, 5, 32, 1000},
+
+		MaxLoad: 1,
+	}
+}
+
+// Runner times cases with testing.Benchmark and feeds the results to its reporters.
+type Runner struct {
+	Config    Config
+	Reporters []Reporter
+	Isolation *Isolation // run each case in a fresh process, if set
+}
+
+func NewRunner(cfg Config, reporters ...Reporter) *Runner {
+	return &Runner{Config: cfg, Reporters: reporters}
+}
+
+// Sizes returns the sizes case c runs at with fixtures of size elements.
+func (r *Runner) Sizes(c Case, size int) []int {
+	if c.Single {
+		return []int{1}
+	}
+	if !c.Vector {
+		return []int{size}
+	}
+	var out []int
+	for _, n := range r.Config.Sweep {
+		if n <= size {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// RunCase times c at size n.
+func RunCase(c Case, fx *Fixtures, n int) Result {
+	br := testing.Benchmark(func(b *testing.B) {
+		c.Bench(b, fx, n)
+	})
+	res := Result{
+		Name:       c.Name,
+		Group:      c.Group,
+		Key:        c.Key(n),
+		Vector:     c.Vector,
+		Size:       n,
+		Iterations: br.N,
+		Extra:      br.Extra,
+	}
+	if br.N > 0 {
+		res.NsPerOp = float64(br.T.Nanoseconds()) / float64(br.N)
+	}
+	return res
+}
+
+// Run initialises the curve, generates fixtures and times every case.
+// Noisy conditions are recorded as warnings in run.Env, or fail the run
+// before any case is timed in strict mode.
+func (r *Runner) Run(cases []Case) (*Run, error) {
+	run := &Run{Curve: r.Config.Curve, Started: time.Now(), Env: CaptureEnvironment()}
+	run.Env.Warnings = NoiseWarnings(r.Config.MaxLoad)
+	if r.Config.Strict && len(run.Env.Warnings) > 0 {
+		return nil, fmt.Errorf("noisy environment: %s", strings.Join(run.Env.Warnings, "; "))
+	}
+	if err := checkAffinity(r.Config.CPUs); err != nil {
+		return nil, err
+	}
+	run.Env.CPUs = r.Config.CPUs
+	run.Env.Procs = r.Config.Procs
+	run.Env.Seed = r.Config.Seed
+	run.Env.GCDisabled = r.Config.DisableGC
+	throttled := throttleCount()
+
+	procs := r.Config.Procs
+	if len(procs) == 0 {
+		procs = []int{runtime.GOMAXPROCS(0)}
+	}
+	for _, c := range cases {
+		run.Planned += len(procs) * len(r.Sizes(c, int(r.Config.Size)))
+	}
+	for _, rep := range r.Reporters {
+		rep.Start(run)
+	}
+
+	emit := func(c Case) func(Result) {
+		return func(res Result) {
+			if res.Iterations == 0 {
+				run.Env.Warnings = append(run.Env.Warnings, fmt.Sprintf("case %s skipped or failed", res.Key))
+				return
+			}
+			run.Results = append(run.Results, res)
+			for _, rep := range r.Reporters {
+				rep.Result(c, res)
+			}
+		}
+	}
+	if r.Isolation != nil {
+		// The parent's own noise warnings come back from every child.
+		seen := make(map[string]bool)
+		for _, w := range run.Env.Warnings {
+			seen[w] = true
+		}
+		warn := func(w string) {
+			if !seen[w] {
+				seen[w] = true
+				run.Env.Warnings = append(run.Env.Warnings, w)
+			}
+		}
+		for _, c := range cases {
+			if err := r.Isolation.run(c, emit(c), warn); err != nil {
+				run.Env.Warnings = append(run.Env.Warnings, fmt.Sprintf("case %s: %v", c.Name, err))
+			}
+		}
+	} else {
+		mcl.InitFromString(r.Config.Curve)
+		var fx *Fixtures
+		if r.Config.Seed != 0 {
+			fx = NewSeededFixtures(r.Config.Size, r.Config.Seed)
+		} else {
+			fx = NewFixtures(r.Config.Size)
+		}
+		if w := gtLayoutWarning(cases, fx); w != "" {
+			run.Env.Warnings = append(run.Env.Warnings, w)
+		}
+		for _, c := range cases {
+			r.timeCase(c, fx, procs, emit(c))
+		}
+	}
+
+	if n := throttleCount() - throttled; n > 0 {
+		run.Env.Warnings = append(run.Env.Warnings, fmt.Sprintf("%d thermal throttling events during the run", n))
+	}
+	if w := loadWarning(r.Config.MaxLoad); w != "" {
+		run.Env.Warnings = append(run.Env.Warnings, "after the run, "+w)
+	}
+	run.Derived = ComputeDerived(run.Values())
+	for _, rep := range r.Reporters {
+		if err := rep.Finish(run); err != nil {
+			return run, err
+		}
+	}
+	return run, nil
+}
+
+// timeCase times c at every size and GOMAXPROCS value.
+func (r *Runner) timeCase(c Case, fx *Fixtures, procs []int, emit func(Result)) {
+	pc := withGCStats(pinned(c, r.Config.CPUs))
+	if r.Config.DisableGC {
+		pc = withoutGC(pc)
+	}
+	for _, n := range r.Sizes(c, fx.Size()) {
+		for _, p := range procs {
+			prev := runtime.GOMAXPROCS(p)
+			for i := 0; i < r.Config.Warmup; i++ {
+				RunCase(pc, fx, n)
+			}
+			res := RunCase(pc, fx, n)
+			runtime.GOMAXPROCS(prev)
+
+			res.Procs = p
+			res.CPUs = r.Config.CPUs
+			if len(procs) > 1 {
+				res.Key = procsKey(res.Key, p)
+			}
+			emit(res)
+		}
+	}
+}
+
+var benchFixtures = make(map[string]*Fixtures)
+
+// Benchmark runs cases as sub-benchmarks <curve>/<case>/n=<size> of b, so
+// registered cases work with go test -bench, -benchmem, -count and benchstat.
+func Benchmark(b *testing.B, cfg Config, curves []string, cases []Case) {
+	r := NewRunner(cfg)
+	for _, curve := range curves {
+		b.Run(curve, func(b *testing.B) {
+			mcl.InitFromString(curve)
+			key := fmt.Sprintf("%s/%d", curve, cfg.Size)
+			fx, ok := benchFixtures[key]
+			if !ok {
+				fx = NewFixtures(cfg.Size)
+				benchFixtures[key] = fx
+			}
+			for _, c := range cases {
+				c := c
+				b.Run(c.Label(), func(b *testing.B) {
+					for _, n := range r.Sizes(c, fx.Size()) {
+						n := n
+						b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+							c.Bench(b, fx, n)
+						})
+					}
+				})
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package mclbench
 
 import (
 	"math"
@@ -8,7 +8,85 @@ import (
 	"github.com/alinush/go-mcl"
 )
 
-func generateG1(count uint64) []mcl.G1 {
+// Fixtures are the random inputs shared by every case of a run.
+type Fixtures struct {
+	G1 []mcl.G1
+	G2 []mcl.G2
+	Fr []mcl.Fr
+	GT []mcl.GT
+
+	g2Precomputed  [][]uint64
+	unitary        []mcl.GT
+	multilinearSRS map[int]*MultilinearSRS
+	pedersen       *PedersenParams
+	ipa            map[int]*IPAParams
+}
+
+func NewFixtures(size uint64) *Fixtures {
+	return &Fixtures{
+		G1: GenerateG1(size),
+		G2: GenerateG2(size),
+		Fr: GenerateFr(size),
+		GT: GenerateGT(size),
+	}
+}
+
+// Size is the number of elements in each fixture slice.
+func (fx *Fixtures) Size() int {
+	return len(fx.Fr)
+}
+
+// G2Precomputed returns the mcl.PrecomputeG2 line coefficients of fx.G2,
+// computed on first use.
+func (fx *Fixtures) G2Precomputed() [][]uint64 {
+	if fx.g2Precomputed == nil {
+		bufLen := mcl.GetUint64NumToPrecompute()
+		fx.g2Precomputed = make([][]uint64, len(fx.G2))
+		for j := range fx.G2 {
+			fx.g2Precomputed[j] = make([]uint64, bufLen)
+			mcl.PrecomputeG2(fx.g2Precomputed[j], &fx.G2[j])
+		}
+	}
+	return fx.g2Precomputed
+}
+
+// Unitary returns the pairings e(G1[j], G2[j]), computed on first use, for
+// the GT cases that need elements of the order-r subgroup. fx.GT holds
+// arbitrary Fp12 elements and is shared with every other case.
+func (fx *Fixtures) Unitary() []mcl.GT {
+	if fx.unitary == nil {
+		fx.unitary = make([]mcl.GT, len(fx.G1))
+		for j := range fx.unitary {
+			mcl.Pairing(&fx.unitary[j], &fx.G1[j], &fx.G2[j])
+		}
+	}
+	return fx.unitary
+}
+
+// NewSeededFixtures derives every fixture from seed, so that separate
+// processes given the same seed time the same inputs.
+func NewSeededFixtures(size uint64, seed int64) *Fixtures {
+	rng := rand.New(rand.NewSource(seed))
+	buf := make([]byte, 64)
+	fx := &Fixtures{
+		G1: make([]mcl.G1, size),
+		G2: make([]mcl.G2, size),
+		Fr: make([]mcl.Fr, size),
+		GT: make([]mcl.GT, size),
+	}
+	for i := uint64(0); i < size; i++ {
+		rng.Read(buf)
+		fx.G1[i].HashAndMapTo(buf)
+		rng.Read(buf)
+		fx.G2[i].HashAndMapTo(buf)
+		rng.Read(buf)
+		fx.Fr[i].SetLittleEndianMod(buf)
+		fx.GT[i].SetInt64(rng.Int63())
+	}
+	return fx
+}
+
+func GenerateG1(count uint64) []mcl.G1 {
 	base := make([]mcl.G1, count)
 	for i := uint64(0); i < count; i++ {
 		base[i].Random()
@@ -16,7 +94,7 @@ func generateG1(count uint64) []mcl.G1 {
 	return base
 }
 
-func generateG2(count uint64) []mcl.G2 {
+func GenerateG2(count uint64) []mcl.G2 {
 	base := make([]mcl.G2, count)
 	for i := uint64(0); i < count; i++ {
 		base[i].Random()
@@ -24,7 +102,7 @@ func generateG2(count uint64) []mcl.G2 {
 	return base
 }
 
-func generateFr(count uint64) []mcl.Fr {
+func GenerateFr(count uint64) []mcl.Fr {
 	base := make([]mcl.Fr, count)
 	for i := uint64(0); i < count; i++ {
 		base[i].Random()
@@ -32,7 +110,7 @@ func generateFr(count uint64) []mcl.Fr {
 	return base
 }
 
-func generateGT(count uint64) []mcl.GT {
+func GenerateGT(count uint64) []mcl.GT {
 	rand.Seed(time.Now().UnixNano())
 	N := int64(math.MaxInt64)
 	var v int64
@@ -0,0 +1,257 @@
+package mclbench
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Working-set sweep: each operation walks fixtures sized from below L1 to
+// well beyond L3. Every iteration walks the whole fixture after one untimed
+// warm-up walk, so the per-element cost shows where the data stops fitting
+// in each cache level.
+
+type WorkingSetPoint struct {
+	Op           string  `json:"op"`
+	Bytes        int     `json:"bytes"`
+	Elements     int     `json:"elements"`
+	Permuted     bool    `json:"permuted"`
+	Passes       int     `json:"passes"` // timed walks over all Elements
+	NsPerElement float64 `json:"ns_per_element"`
+}
+
+// Key is the results key of the point, <Op>_ws<bytes> with a _rand suffix
+// for permuted access.
+func (p WorkingSetPoint) Key() string {
+	k := fmt.Sprintf("%s_ws%d", p.Op, p.Bytes)
+	if p.Permuted {
+		k += "_rand"
+	}
+	return k
+}
+
+// workingSetKey matches WorkingSetPoint keys.
+var workingSetKey = regexp.MustCompile(`^(.+)_ws(\d+)(_rand)?$`)
+
+// workingSetOp is one operation over element j of fixtures of count elements.
+type workingSetOp struct {
+	name  string
+	bytes int // bytes of fixture data touched per element
+	setup func(count int) func(j int)
+}
+
+// pool is the number of distinct random elements; larger fixtures repeat
+// them at different addresses, which is all the caches see.
+const pool = 256
+
+func tileG1(count int) []mcl.G1 {
+	out := make([]mcl.G1, count)
+	copy(out, GenerateG1(pool))
+	for i := pool; i < count; i++ {
+		out[i] = out[i%pool]
+	}
+	return out
+}
+
+func tileG2(count int) []mcl.G2 {
+	out := make([]mcl.G2, count)
+	copy(out, GenerateG2(pool))
+	for i := pool; i < count; i++ {
+		out[i] = out[i%pool]
+	}
+	return out
+}
+
+func tileFr(count int) []mcl.Fr {
+	out := make([]mcl.Fr, count)
+	copy(out, GenerateFr(pool))
+	for i := pool; i < count; i++ {
+		out[i] = out[i%pool]
+	}
+	return out
+}
+
+var (
+	g1Bytes = int(unsafe.Sizeof(mcl.G1{}))
+	g2Bytes = int(unsafe.Sizeof(mcl.G2{}))
+	frBytes = int(unsafe.Sizeof(mcl.Fr{}))
+)
+
+func workingSetOps() []workingSetOp {
+	return []workingSetOp{
+		{"G1Add", g1Bytes, func(count int) func(int) {
+			xs := tileG1(count)
+			var acc mcl.G1
+			return func(j int) { mcl.G1Add(&acc, &acc, &xs[j]) }
+		}},
+		{"G1Mul", g1Bytes + frBytes, func(count int) func(int) {
+			xs, ks := tileG1(count), tileFr(count)
+			var r mcl.G1
+			return func(j int) { mcl.G1Mul(&r, &xs[j], &ks[j]) }
+		}},
+		{"FrMul", frBytes, func(count int) func(int) {
+			ks := tileFr(count)
+			var acc mcl.Fr
+			acc.SetInt64(1)
+			return func(j int) { mcl.FrMul(&acc, &acc, &ks[j]) }
+		}},
+		{"Pairing", g1Bytes + g2Bytes, func(count int) func(int) {
+			ps, qs := tileG1(count), tileG2(count)
+			var e mcl.GT
+			return func(j int) { mcl.Pairing(&e, &ps[j], &qs[j]) }
+		}},
+	}
+}
+
+// WorkingSetSizes returns working-set sizes in bytes around each data cache
+// level (half and double its size) and far beyond the last one, up to max.
+func WorkingSetSizes(caches []Cache, max int) []int {
+	var levels []int
+	for _, c := range caches {
+		if c.Type != "Instruction" && c.Bytes > 0 {
+			levels = append(levels, c.Bytes)
+		}
+	}
+	if len(levels) == 0 {
+		levels = []int{32 << 10, 1 << 20, 32 << 20}
+	}
+	sort.Ints(levels)
+	seen := make(map[int]bool)
+	var out []int
+	add := func(b int) {
+		if b <= max && !seen[b] {
+			seen[b] = true
+			out = append(out, b)
+		}
+	}
+	for _, l := range levels {
+		add(l / 2)
+		add(l)
+		add(2 * l)
+	}
+	add(8 * levels[len(levels)-1])
+	sort.Ints(out)
+	return out
+}
+
+type WorkingSetConfig struct {
+	Ops      []string // operations to run, all if empty
+	Sizes    []int    // working-set sizes in bytes
+	Access   []bool   // false for sequential access, true for a random permutation
+	Seed     int64
+	Progress io.Writer
+}
+
+// RunWorkingSet times every selected operation at every size and access pattern.
+// It fails before timing anything if an operation is unknown, and warns on
+// Progress about operations whose elements are larger than every size.
+func RunWorkingSet(cfg WorkingSetConfig) ([]WorkingSetPoint, error) {
+	ops := workingSetOps()
+	known := make(map[string]bool)
+	for _, op := range ops {
+		known[op.name] = true
+	}
+	wanted := make(map[string]bool)
+	for _, s := range cfg.Ops {
+		if !known[s] {
+			return nil, fmt.Errorf("unknown operation %q", s)
+		}
+		wanted[s] = true
+	}
+	progress := cfg.Progress
+	if progress == nil {
+		progress = ioutil.Discard
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	var points []WorkingSetPoint
+	for _, op := range ops {
+		if len(wanted) > 0 && !wanted[op.name] {
+			continue
+		}
+		fits := false
+		for _, size := range cfg.Sizes {
+			fits = fits || size >= op.bytes
+		}
+		if !fits {
+			fmt.Fprintf(progress, "WARNING: %s skipped: every working-set size is below its %d bytes per element\n", op.name, op.bytes)
+			continue
+		}
+		for _, size := range cfg.Sizes {
+			count := size / op.bytes
+			if count < 1 {
+				continue
+			}
+			run := op.setup(count)
+			for _, permuted := range cfg.Access {
+				var order []uint32
+				if permuted {
+					order = make([]uint32, count)
+					for i, j := range rng.Perm(count) {
+						order[i] = uint32(j)
+					}
+				}
+				pass := func() {
+					for i := 0; i < count; i++ {
+						j := i
+						if order != nil {
+							j = int(order[i])
+						}
+						run(j)
+					}
+				}
+				br := testing.Benchmark(func(b *testing.B) {
+					pass()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						pass()
+					}
+				})
+				pt := WorkingSetPoint{Op: op.name, Bytes: count * op.bytes, Elements: count, Permuted: permuted, Passes: br.N}
+				if br.N > 0 {
+					pt.NsPerElement = float64(br.T.Nanoseconds()) / float64(br.N) / float64(count)
+				}
+				points = append(points, pt)
+				fmt.Fprintf(progress, "%-60s %20.3f us\n", fmt.Sprintf("%s (%s working set, %s elements%s):", op.name, humanBytes(pt.Bytes), strconv.Itoa(count), accessLabel(permuted)), pt.NsPerElement/1000)
+			}
+		}
+		fmt.Fprintln(progress, SepString(""))
+	}
+	return points, nil
+}
+
+func accessLabel(permuted bool) string {
+	if permuted {
+		return ", random order"
+	}
+	return ""
+}
+
+func humanBytes(b int) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(b)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", b)
+}
+
+// WorkingSetValues flattens points into the results-file map.
+func WorkingSetValues(points []WorkingSetPoint) map[string]float64 {
+	db := make(map[string]float64)
+	for _, p := range points {
+		db[p.Key()] = p.NsPerElement
+	}
+	return db
+}